@@ -0,0 +1,62 @@
+package game2
+
+import "github.com/jakecoffman/cp"
+
+const WINDOW_HEALTH = 100.0
+const WINDOW_BREAK_IMPULSE = 0.5
+const WINDOW_DAMAGE = 200.0
+const WINDOW_BREAK_LOUDNESS = 10.0
+
+const (
+	CollisionDefault = cp.CollisionType(iota)
+	CollisionPlayer
+	CollisionMonster
+	CollisionWindow
+)
+
+type ContactDamager interface {
+	ContactDamage() float64
+}
+
+func (g *Game) AddCollisionHandlers() {
+	playerMonster := g.Space.NewCollisionHandler(CollisionPlayer, CollisionMonster)
+	playerMonster.PostSolveFunc = func(arb *cp.Arbiter, space *cp.Space, data interface{}) {
+		_, other := arb.Shapes()
+
+		damager, ok := other.UserData.(ContactDamager)
+		if !ok || damager.ContactDamage() <= 0 {
+			return
+		}
+
+		g.Player.Hurt(g, damager.ContactDamage(), Vec2FromCP(arb.Normal().Neg()))
+	}
+
+	window := g.Space.NewWildcardCollisionHandler(CollisionWindow)
+	window.PostSolveFunc = func(arb *cp.Arbiter, space *cp.Space, data interface{}) {
+		shape, _ := arb.Shapes()
+
+		ref := shape.UserData.(FaceRef)
+		face := ref.Face()
+		impulse := arb.TotalImpulse().Length()
+
+		if !face.Breakable || impulse < WINDOW_BREAK_IMPULSE {
+			return
+		}
+
+		face.Damage += (impulse - WINDOW_BREAK_IMPULSE) * WINDOW_DAMAGE
+		if face.Damage < WINDOW_HEALTH {
+			return
+		}
+
+		// Shapes can't be removed while the space is being stepped.
+		space.AddPostStepCallback(func(space *cp.Space, key interface{}, data interface{}) {
+			if ref.Face().Type != FaceWindow {
+				return
+			}
+			g.Level.BreakFace(g, ref)
+
+			pos := ref.Cell.Position.AddXYZ(0.5, 0, 0.5).Add(FACE_DIRECTION[ref.Index].Scale(0.5))
+			g.EmitNoise(pos, WINDOW_BREAK_LOUDNESS, NoiseImpact, GroupStatic)
+		}, ref, nil)
+	}
+}
@@ -16,6 +16,9 @@ const (
 	TOOL_WALLS = EditorTool(iota)
 	TOOL_FLOOR
 	TOOL_PLAY
+	TOOL_ENTITY
+	TOOL_POWER
+	TOOL_LIGHT
 )
 
 type Editor struct {
@@ -27,9 +30,12 @@ type Editor struct {
 	Y             float64
 	HitPos        Vec3
 
-	Tool      EditorTool
-	ToolFloor ToolFloor
-	ToolWall  ToolWall
+	Tool       EditorTool
+	ToolFloor  ToolFloor
+	ToolWall   ToolWall
+	ToolEntity ToolEntity
+	ToolPower  ToolPower
+	ToolLight  ToolLight
 }
 
 func NewEditor() *Editor {
@@ -63,6 +69,18 @@ func (e *Editor) Update(g *Game, dt time.Duration) {
 		e.Tool = TOOL_PLAY
 	}
 
+	if rl.IsKeyPressed(rl.KeyFour) {
+		e.Tool = TOOL_ENTITY
+	}
+
+	if rl.IsKeyPressed(rl.KeyFive) {
+		e.Tool = TOOL_POWER
+	}
+
+	if rl.IsKeyPressed(rl.KeySix) {
+		e.Tool = TOOL_LIGHT
+	}
+
 	if e.Tool != TOOL_PLAY {
 
 		forward := e.Camera.Target.Subtract(e.Camera.Position).Normalize()
@@ -154,6 +172,12 @@ func (e *Editor) Update(g *Game, dt time.Duration) {
 		e.ToolWall.Update(g, e)
 	case TOOL_FLOOR:
 		e.ToolFloor.Update(g, e)
+	case TOOL_ENTITY:
+		e.ToolEntity.Update(g, e)
+	case TOOL_POWER:
+		e.ToolPower.Update(g, e)
+	case TOOL_LIGHT:
+		e.ToolLight.Update(g, e)
 	case TOOL_PLAY:
 		g.Update(dt)
 	}
@@ -175,15 +199,11 @@ func (e *Editor) Draw(g *Game) {
 		g.Draw3D(maxY)
 
 		BeginOverlayMode(func() {
-			if g.Monster != nil {
-				g.Monster.PathFinder.Draw3D(g)
-
-				for _, arm := range g.Monster.arms {
-					tip := arm.Tip()
+			g.DrawNoises()
 
-					rl.DrawLine3D(tip.Position3D().Raylib(), arm.tipTarget.Raylib(), rl.Blue)
-				}
-			}
+			g.DrawEntityOverlays()
+			g.Level.Power.DrawOverlay(g)
+			g.Level.DrawLightsOverlay(g)
 
 			if g.RenderFlags&(RENDER_FLAG_PHYSICS) != 0 {
 				drawer := NewPhysicsDrawer(float64(maxY), true, true, true)
@@ -219,6 +239,12 @@ func (e *Editor) Draw(g *Game) {
 				e.ToolWall.Draw3D(g, e)
 			case TOOL_FLOOR:
 				e.ToolFloor.Draw3D(g, e)
+			case TOOL_ENTITY:
+				e.ToolEntity.Draw3D(g, e)
+			case TOOL_POWER:
+				e.ToolPower.Draw3D(g, e)
+			case TOOL_LIGHT:
+				e.ToolLight.Draw3D(g, e)
 			}
 
 		})
@@ -245,6 +271,12 @@ func (e *Editor) Draw(g *Game) {
 		e.ToolWall.DrawHUD(g, e)
 	case TOOL_FLOOR:
 		e.ToolFloor.DrawHUD(g, e)
+	case TOOL_ENTITY:
+		e.ToolEntity.DrawHUD(g, e)
+	case TOOL_POWER:
+		e.ToolPower.DrawHUD(g, e)
+	case TOOL_LIGHT:
+		e.ToolLight.DrawHUD(g, e)
 	}
 
 }
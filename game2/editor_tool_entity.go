@@ -0,0 +1,165 @@
+package game2
+
+import (
+	"image/color"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gen2brain/raylib-go/raygui"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+type EntityKind = uint8
+
+const (
+	EntityKindMonster = EntityKind(iota)
+	EntityKindElevator
+	EntityKindKeycard
+	EntityKindItem
+)
+
+type ToolEntity struct {
+	CellPos      Vec3
+	Kind         EntityKind
+	MonsterType  string
+	KeycardLevel int
+	ItemIndex    int
+	Facing       FaceIndex
+}
+
+func (t *ToolEntity) Update(g *Game, e *Editor) {
+	t.CellPos = NewVec3(math.Floor(e.HitPos.X), e.HitPos.Y, math.Floor(e.HitPos.Z))
+	center := t.CellPos.AddXYZ(0.5, 0, 0.5)
+
+	fx := e.HitPos.X - t.CellPos.X - 0.5
+	fz := e.HitPos.Z - t.CellPos.Z - 0.5
+
+	if math.Abs(fx) > math.Abs(fz) {
+		if fx < 0 {
+			t.Facing = FACE_EAST
+		} else {
+			t.Facing = FACE_WEST
+		}
+	} else {
+		if fz > 0 {
+			t.Facing = FACE_NORTH
+		} else {
+			t.Facing = FACE_SOUTH
+		}
+	}
+
+	if rl.IsMouseButtonPressed(rl.MouseButtonRight) {
+		switch t.Kind {
+		case EntityKindMonster:
+			g.Spawn(&Monster{
+				Type: t.MonsterType,
+				Y:    center.Y,
+				Body: BodyState{Position: center.To2D()},
+			})
+		case EntityKindElevator:
+			t.ToggleLanding(g)
+		case EntityKindKeycard:
+			g.SpawnItem(&Keycard{Level: t.KeycardLevel}, center)
+		case EntityKindItem:
+			g.SpawnItem(ITEM_CATALOGUE[t.ItemIndex](), center)
+		}
+	}
+
+	if rl.IsMouseButtonPressed(rl.MouseButtonMiddle) {
+		if entity := g.NearestEntity(center, 1); entity != nil {
+			g.Despawn(entity.Base().ID)
+		}
+	}
+}
+
+// ToggleLanding adds or removes a landing at the hovered cell, starting a new elevator when
+// the column has none.
+func (t *ToolEntity) ToggleLanding(g *Game) {
+	elevator := g.Level.ElevatorAt(t.CellPos)
+
+	if elevator == nil {
+		g.Spawn(&Elevator{
+			Column:   t.CellPos.To2D(),
+			Facing:   t.Facing,
+			Landings: []float64{t.CellPos.Y},
+			Y:        t.CellPos.Y,
+		})
+		return
+	}
+
+	elevator.Despawn(g)
+
+	if elevator.HasLanding(t.CellPos.Y) {
+		elevator.Landings = slices.DeleteFunc(elevator.Landings, func(y float64) bool {
+			return y == t.CellPos.Y
+		})
+	} else {
+		elevator.Landings = append(elevator.Landings, t.CellPos.Y)
+	}
+	elevator.Calls = nil
+
+	if len(elevator.Landings) == 0 {
+		g.Despawn(elevator.ID)
+		return
+	}
+	elevator.Spawn(g)
+}
+
+func (t *ToolEntity) Draw3D(g *Game, e *Editor) {
+	col := rl.White
+	if rl.IsMouseButtonDown(rl.MouseButtonRight) {
+		col = color.RGBA{255, 0, 0, 255}
+	}
+
+	rl.DrawCubeWiresV(t.CellPos.AddXYZ(0.5, 0.5, 0.5).Raylib(), XYZ.Raylib(), col)
+
+	if t.Kind == EntityKindElevator {
+		rl.DrawModelWiresEx(g.GetModel("wallDebug"), t.CellPos.AddXYZ(0.5, 0.5, 0.5).Raylib(), Y.Negate().Raylib(), float32(FACE_DEGREE[t.Facing]), XYZ.Raylib(), col)
+		return
+	}
+
+	if entity := g.NearestEntity(t.CellPos.AddXYZ(0.5, 0, 0.5), 1); entity != nil {
+		rl.DrawSphereWires(entity.Position3D().Raylib(), 0.5, 6, 6, rl.Red)
+	}
+}
+
+func (t *ToolEntity) DrawHUD(g *Game, e *Editor) {
+	size := float64(30)
+	line := NewLineLayout(0, 50, size)
+
+	entries, _ := os.ReadDir(MONSTER_DEFINITIONS_PATH)
+
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+
+		if raygui.Toggle(line.Next(size*4), raygui.IconText(raygui.ICON_DEMON, name), t.Kind == EntityKindMonster && t.MonsterType == name) {
+			t.MonsterType = name
+			t.Kind = EntityKindMonster
+		}
+		line.Break(size)
+	}
+
+	if raygui.Toggle(line.Next(size*4), raygui.IconText(raygui.ICON_ARROW_UP_FILL, "elevator"), t.Kind == EntityKindElevator) {
+		t.Kind = EntityKindElevator
+	}
+	line.Break(size)
+
+	if raygui.Toggle(line.Next(size*4), raygui.IconText(raygui.ICON_KEY, "keycard"), t.Kind == EntityKindKeycard) {
+		t.Kind = EntityKindKeycard
+	}
+	level := int32(t.KeycardLevel)
+	raygui.Spinner(line.Next(size*3), "", &level, 0, KEYCARD_LEVELS, false)
+	t.KeycardLevel = int(level)
+	line.Break(size)
+
+	for i, newItem := range ITEM_CATALOGUE {
+		if raygui.Toggle(line.Next(size*4), raygui.IconText(raygui.ICON_BOX, newItem().Name()), t.Kind == EntityKindItem && t.ItemIndex == i) {
+			t.Kind = EntityKindItem
+			t.ItemIndex = i
+		}
+		line.Break(size)
+	}
+}
@@ -76,6 +76,12 @@ func (t *ToolFloor) DrawHUD(g *Game, e *Editor) {
 	if raygui.Toggle(line.Next(size), raygui.IconText(raygui.ICON_VERTICAL_BARS, ""), t.Paste.Type == GroundStair) {
 		t.Paste.Type = GroundStair
 	}
+	if raygui.Toggle(line.Next(size), raygui.IconText(raygui.ICON_LAYERS, ""), t.Paste.Type == GroundLadder) {
+		t.Paste.Type = GroundLadder
+	}
+
+	line.Next(size)
+	t.Paste.Checkpoint = raygui.Toggle(line.Next(size), raygui.IconText(raygui.ICON_PLAYER, ""), t.Paste.Checkpoint)
 
 	line.Break(size)
 
@@ -0,0 +1,89 @@
+package game2
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/gen2brain/raylib-go/raygui"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// ToolLight hangs a light from the ceiling of the hovered cell with a right click; spot
+// lights point straight down. A middle click takes the cell's light down again.
+type ToolLight struct {
+	CellPos Vec3
+	Paste   PlacedLight
+	Hue     float64
+	Tint    float64
+}
+
+func (t *ToolLight) Update(g *Game, e *Editor) {
+	t.CellPos = NewVec3(math.Floor(e.HitPos.X), e.HitPos.Y, math.Floor(e.HitPos.Z))
+
+	if t.Paste.Radius == 0 {
+		t.Paste = PlacedLight{
+			Type:        LIGHT_POINT,
+			Color:       rl.White,
+			Strength:    1,
+			Radius:      4,
+			CutOff:      30,
+			OuterCutOff: 45,
+		}
+	}
+
+	if rl.IsMouseButtonPressed(rl.MouseButtonRight) && g.Level.LightAt(t.CellPos) < 0 {
+		light := t.Paste
+		light.Position = t.CellPos.AddXYZ(0.5, 0.9, 0.5)
+		light.Target = light.Position.Subtract(Y)
+
+		g.Level.Lights = append(g.Level.Lights, light)
+	}
+
+	if rl.IsMouseButtonPressed(rl.MouseButtonMiddle) {
+		if i := g.Level.LightAt(t.CellPos); i >= 0 {
+			g.Level.Lights = append(g.Level.Lights[:i], g.Level.Lights[i+1:]...)
+		}
+	}
+}
+
+func (t *ToolLight) Draw3D(g *Game, e *Editor) {
+	col := rl.White
+	if rl.IsMouseButtonDown(rl.MouseButtonRight) {
+		col = color.RGBA{255, 0, 0, 255}
+	}
+
+	rl.DrawCubeWiresV(t.CellPos.AddXYZ(0.5, 0.5, 0.5).Raylib(), XYZ.Raylib(), col)
+	rl.DrawSphereWires(t.CellPos.AddXYZ(0.5, 0.9, 0.5).Raylib(), float32(t.Paste.Radius), 6, 12, t.Paste.Color)
+}
+
+func (t *ToolLight) DrawHUD(g *Game, e *Editor) {
+	size := float64(30)
+	line := NewLineLayout(0, 50, size)
+
+	if raygui.Toggle(line.Next(size*4), raygui.IconText(raygui.ICON_TARGET_SMALL, "point"), t.Paste.Type == LIGHT_POINT) {
+		t.Paste.Type = LIGHT_POINT
+	}
+	line.Break(size)
+
+	if raygui.Toggle(line.Next(size*4), raygui.IconText(raygui.ICON_ARROW_DOWN_FILL, "spot"), t.Paste.Type == LIGHT_SPOT) {
+		t.Paste.Type = LIGHT_SPOT
+	}
+	line.Break(size)
+
+	slider := func(label string, value *float64, minValue float64, maxValue float64) {
+		*value = float64(raygui.Slider(line.Next(size*4), "", fmt.Sprintf("%s %.1f", label, *value), float32(*value), float32(minValue), float32(maxValue)))
+		line.Break(size)
+	}
+
+	slider("hue", &t.Hue, 0, 360)
+	slider("tint", &t.Tint, 0, 1)
+	slider("strength", &t.Paste.Strength, 0, 3)
+	slider("radius", &t.Paste.Radius, 1, 10)
+	if t.Paste.Type == LIGHT_SPOT {
+		slider("cone", &t.Paste.OuterCutOff, 10, 80)
+		t.Paste.CutOff = t.Paste.OuterCutOff * 2 / 3
+	}
+
+	t.Paste.Color = rl.ColorFromHSV(float32(t.Hue), float32(t.Tint), 1)
+}
@@ -0,0 +1,104 @@
+package game2
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/gen2brain/raylib-go/raygui"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// ToolPower places power nodes with a right click on an empty cell. Right clicking a node
+// selects it, and right clicking another one while selected wires the two together or cuts
+// the wire between them.
+type ToolPower struct {
+	CellPos  Vec3
+	Kind     PowerKind
+	Facing   FaceIndex
+	Selected PowerID
+}
+
+func (t *ToolPower) Update(g *Game, e *Editor) {
+	t.CellPos = NewVec3(math.Floor(e.HitPos.X), e.HitPos.Y, math.Floor(e.HitPos.Z))
+	network := &g.Level.Power
+
+	fx := e.HitPos.X - t.CellPos.X - 0.5
+	fz := e.HitPos.Z - t.CellPos.Z - 0.5
+
+	if math.Abs(fx) > math.Abs(fz) {
+		if fx < 0 {
+			t.Facing = FACE_EAST
+		} else {
+			t.Facing = FACE_WEST
+		}
+	} else {
+		if fz > 0 {
+			t.Facing = FACE_NORTH
+		} else {
+			t.Facing = FACE_SOUTH
+		}
+	}
+
+	hovered := network.Nearest(e.HitPos, 0.3)
+
+	if rl.IsMouseButtonPressed(rl.MouseButtonRight) {
+		switch {
+		case hovered == nil:
+			network.Add(&PowerNode{
+				Kind:     t.Kind,
+				Position: t.CellPos,
+				Face:     t.Facing,
+				On:       true,
+			})
+		case t.Selected != 0 && t.Selected != hovered.ID:
+			network.Connect(t.Selected, hovered.ID)
+			t.Selected = 0
+		default:
+			t.Selected = hovered.ID
+		}
+	}
+
+	if rl.IsMouseButtonPressed(rl.MouseButtonMiddle) && hovered != nil {
+		network.Remove(hovered.ID)
+		if t.Selected == hovered.ID {
+			t.Selected = 0
+		}
+	}
+
+	network.Solve()
+}
+
+func (t *ToolPower) Draw3D(g *Game, e *Editor) {
+	col := rl.White
+	if rl.IsMouseButtonDown(rl.MouseButtonRight) {
+		col = color.RGBA{255, 0, 0, 255}
+	}
+
+	rl.DrawCubeWiresV(t.CellPos.AddXYZ(0.5, 0.5, 0.5).Raylib(), XYZ.Raylib(), col)
+
+	if t.Kind == PowerDoor {
+		rl.DrawModelWiresEx(g.GetModel("wallDebug"), t.CellPos.AddXYZ(0.5, 0.5, 0.5).Raylib(), Y.Negate().Raylib(), float32(FACE_DEGREE[t.Facing]), XYZ.Raylib(), col)
+	}
+
+	if selected := g.Level.Power.Node(t.Selected); selected != nil {
+		rl.DrawSphereWires(selected.Center().Raylib(), 0.15, 6, 6, rl.Yellow)
+		rl.DrawLine3D(selected.Center().Raylib(), e.HitPos.Add(Y.Scale(0.5)).Raylib(), rl.Yellow)
+	}
+}
+
+func (t *ToolPower) DrawHUD(g *Game, e *Editor) {
+	size := float64(30)
+	line := NewLineLayout(0, 50, size)
+
+	for kind, name := range POWER_KIND_NAMES {
+		if raygui.Toggle(line.Next(size*4), raygui.IconText(raygui.ICON_WAVE_SQUARE, name), t.Kind == PowerKind(kind)) {
+			t.Kind = PowerKind(kind)
+		}
+		line.Break(size)
+	}
+
+	if selected := g.Level.Power.Node(t.Selected); selected != nil && (selected.Kind == PowerSource || selected.Kind == PowerSwitch) {
+		selected.On = raygui.CheckBox(line.Next(size), "On", selected.On)
+		line.Break(size)
+	}
+}
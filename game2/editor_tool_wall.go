@@ -52,12 +52,14 @@ func (t *ToolWall) Update(g *Game, e *Editor) {
 	if rl.IsMouseButtonDown(rl.MouseButtonMiddle) {
 		cellRef := g.Level.GetCell(t.CellPos)
 
-		t.Paste = cellRef.Faces[t.FaceIndex]
+		t.Paste = cellRef.Faces[t.FaceIndex].Saved()
 	}
 
 	if rl.IsMouseButtonDown(rl.MouseButtonRight) {
-		cellRef := g.Level.GetCell(t.CellPos)
-		cellRef.Faces[t.FaceIndex] = t.Paste
+		ref := FaceRef{g.Level.GetCell(t.CellPos), t.FaceIndex}
+		if ref.Face().Saved() != t.Paste {
+			g.Level.ReplaceFace(g, ref, t.Paste)
+		}
 	}
 }
 
@@ -91,6 +93,34 @@ func (t *ToolWall) DrawHUD(g *Game, e *Editor) {
 	if raygui.Toggle(line.Next(size), raygui.IconText(raygui.ICON_DOOR, ""), t.Paste.Type == FaceDoor) {
 		t.Paste.Type = FaceDoor
 	}
+	if raygui.Toggle(line.Next(size), raygui.IconText(raygui.ICON_WINDOW, ""), t.Paste.Type == FaceWindow) {
+		t.Paste.Type = FaceWindow
+	}
+
+	// Only windows can break.
+	if t.Paste.Type == FaceWindow {
+		line.Next(size)
+		t.Paste.Breakable = raygui.Toggle(line.Next(size), raygui.IconText(raygui.ICON_CRACK, ""), t.Paste.Breakable)
+	} else {
+		t.Paste.Breakable = false
+	}
+
+	line.Break(size)
+
+	if raygui.Toggle(line.Next(size), raygui.IconText(raygui.ICON_LOCK_OPEN, ""), t.Paste.Lock == LockUnlocked) {
+		t.Paste.Lock = LockUnlocked
+	}
+	if raygui.Toggle(line.Next(size), raygui.IconText(raygui.ICON_LOCK_CLOSE, ""), t.Paste.Lock == LockLocked) {
+		t.Paste.Lock = LockLocked
+	}
+	if raygui.Toggle(line.Next(size), raygui.IconText(raygui.ICON_KEY, ""), t.Paste.Lock == LockKeycard) {
+		t.Paste.Lock = LockKeycard
+	}
+	if t.Paste.Lock == LockKeycard {
+		level := int32(t.Paste.LockLevel)
+		raygui.Spinner(line.Next(size*3), "", &level, 0, KEYCARD_LEVELS, false)
+		t.Paste.LockLevel = int(level)
+	}
 
 	line.Break(size)
 
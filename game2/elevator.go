@@ -0,0 +1,282 @@
+package game2
+
+import (
+	"encoding/gob"
+	"math"
+	"slices"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/jakecoffman/cp"
+)
+
+const ELEVATOR_SPEED = 1.0
+const ELEVATOR_DOOR_TIME = 3 * time.Second
+const ELEVATOR_WAIT_COST = 4.0
+const ELEVATOR_RIDE_TOLERANCE = 0.1
+
+func init() {
+	gob.Register(&Elevator{})
+}
+
+// Elevator is a car moving up and down the column of cells at Column. Each landing has a
+// door on the Facing side that only opens while the car is stopped there.
+type Elevator struct {
+	EntityBase
+
+	Column   Vec2
+	Facing   FaceIndex
+	Landings []float64
+	Y        float64
+	Calls    []float64
+	TileX    int
+	TileY    int
+
+	doorsOpenUntil time.Duration
+	gates          []*cp.Shape
+}
+
+func (e *Elevator) Position3D() Vec3 {
+	return NewVec3(e.Column.X+0.5, e.Y, e.Column.Y+0.5)
+}
+
+func (e *Elevator) Contains(pos Vec3) bool {
+	return math.Floor(pos.X) == e.Column.X && math.Floor(pos.Z) == e.Column.Y
+}
+
+// Carries tells whether something at pos is standing on the car.
+func (e *Elevator) Carries(pos Vec3) bool {
+	return e.Contains(pos) && math.Abs(pos.Y-e.Y) < ELEVATOR_RIDE_TOLERANCE
+}
+
+func (e *Elevator) HasLanding(y float64) bool {
+	return slices.Contains(e.Landings, math.Floor(y))
+}
+
+func (e *Elevator) Call(y float64) {
+	y = math.Floor(y)
+	if e.HasLanding(y) && !slices.Contains(e.Calls, y) {
+		e.Calls = append(e.Calls, y)
+	}
+}
+
+// Button is where the call button of a landing sits, beside the door on the landing side.
+func (e *Elevator) Button(landing float64) Vec3 {
+	return NewVec3(e.Column.X+0.5, landing, e.Column.Y+0.5).Add(FACE_DIRECTION[e.Facing].Scale(0.6))
+}
+
+func (e *Elevator) Spawn(g *Game) {
+	slices.Sort(e.Landings)
+	if len(e.Landings) > 0 && !e.HasLanding(e.Y) {
+		e.Y = e.Landings[0]
+	}
+
+	transform := cp.NewTransformTranslate(e.Column.CP())
+	e.gates = make([]*cp.Shape, len(e.Landings))
+
+	for i, landing := range e.Landings {
+		gate := cp.NewPolyShape(g.Space.StaticBody, 4, WALL_VERTS[e.Facing], transform, 0)
+		gate.Filter.Group = GroupStatic
+		gate.Filter.Categories = Category(landing, true, false)
+		gate.Filter.Mask = Category(landing, true, true)
+
+		e.gates[i] = g.Space.AddShape(gate)
+	}
+
+	g.Level.elevators = append(g.Level.elevators, e)
+}
+
+func (e *Elevator) Despawn(g *Game) {
+	for _, gate := range e.gates {
+		if g.Space.ContainsShape(gate) {
+			g.Space.RemoveShape(gate)
+		}
+	}
+	g.Level.elevators = slices.DeleteFunc(g.Level.elevators, func(other *Elevator) bool {
+		return other == e
+	})
+}
+
+func (e *Elevator) Save(g *Game) {}
+
+func (e *Elevator) Update(g *Game) {
+	if g.Time < e.doorsOpenUntil {
+		return
+	}
+	e.SetDoorsOpen(g, false)
+
+	if len(e.Calls) == 0 {
+		return
+	}
+
+	if !e.Powered(g) {
+		return
+	}
+
+	target := e.Calls[0]
+
+	if e.Y == target {
+		e.Calls = e.Calls[1:]
+		e.SetDoorsOpen(g, true)
+		e.doorsOpenUntil = g.Time + ELEVATOR_DOOR_TIME
+		return
+	}
+
+	step := ELEVATOR_SPEED * g.TimeDelta.Seconds()
+	y := e.Y
+	if target > y {
+		y = min(y+step, target)
+	} else {
+		y = max(y-step, target)
+	}
+
+	e.Carry(g, y-e.Y)
+	e.Y = y
+}
+
+// Carry moves everyone standing on the car along with it.
+func (e *Elevator) Carry(g *Game, dy float64) {
+	if p := g.Player; e.Carries(p.Position3D()) {
+		p.Y += dy
+		p.YVelocity = 0
+		SetFloorFilter(p.shape, p.Y)
+	}
+
+	EachEntity(g, func(m *Monster) {
+		if e.Carries(m.Position3D()) {
+			m.Ride(dy)
+		}
+	})
+}
+
+func (e *Elevator) SetDoorsOpen(g *Game, open bool) {
+	for i, landing := range e.Landings {
+		gate := e.gates[i]
+		atLanding := open && landing == e.Y
+
+		if atLanding && g.Space.ContainsShape(gate) {
+			g.Space.RemoveShape(gate)
+		}
+		if !atLanding && !g.Space.ContainsShape(gate) {
+			g.Space.AddShape(gate)
+		}
+	}
+}
+
+func (e *Elevator) DoorsOpen(g *Game) bool {
+	return g.Time < e.doorsOpenUntil
+}
+
+// ElevatorButton calls the car to its landing, or sends it on to the next landing from
+// inside the car.
+type ElevatorButton struct {
+	Elevator *Elevator
+	Landing  float64
+	Inside   bool
+}
+
+func (e *Elevator) Interactables(g *Game) []Interactable {
+	buttons := make([]Interactable, 0, len(e.Landings)+1)
+	for _, landing := range e.Landings {
+		buttons = append(buttons, ElevatorButton{Elevator: e, Landing: landing})
+	}
+	if e.Carries(g.Player.Position3D()) {
+		buttons = append(buttons, ElevatorButton{Elevator: e, Landing: e.Y, Inside: true})
+	}
+	return buttons
+}
+
+func (b ElevatorButton) InteractPosition() Vec3 {
+	if b.Inside {
+		return b.Elevator.Position3D().Add(Y.Scale(0.5))
+	}
+	return b.Elevator.Button(b.Landing).Add(Y.Scale(0.5))
+}
+
+func (b ElevatorButton) InteractPrompt(g *Game, p *Player) string {
+	if b.Inside {
+		return "Next floor"
+	}
+	return "Call elevator"
+}
+
+func (b ElevatorButton) Interact(g *Game, p *Player) {
+	e := b.Elevator
+	if b.Inside {
+		index := slices.Index(e.Landings, e.Y)
+		e.Call(e.Landings[(index+1)%len(e.Landings)])
+		return
+	}
+	e.Call(b.Landing)
+}
+
+func (e *Elevator) Draw3D(g *Game, maxY int) {
+	aa, bb := g.Tileset.GetAABB(e.TileX, e.TileY)
+	g.MainShader.UVClamp.Set(aa.X, aa.Y, bb.X, bb.Y)
+
+	if math.Floor(e.Y) <= float64(maxY) {
+		center := NewVec3(e.Column.X, e.Y, e.Column.Y).Add(NewVec3(0.5, 0.5-WALL_WIDTH+0.01, 0.5))
+		rl.DrawModelEx(g.GetModel("wall"), center.Raylib(), Z.Raylib(), float32(-90), XYZ.Raylib(), rl.White)
+	}
+
+	for i, landing := range e.Landings {
+		if landing > float64(maxY) || !g.Space.ContainsShape(e.gates[i]) {
+			continue
+		}
+		center := NewVec3(e.Column.X+0.5, landing+0.5, e.Column.Y+0.5)
+		rl.DrawModelEx(g.GetModel("wall"), center.Raylib(), Y.Negate().Raylib(), float32(FACE_DEGREE[e.Facing]), XYZ.Raylib(), rl.Gray)
+	}
+}
+
+func (e *Elevator) DrawOverlay(g *Game) {
+	for _, landing := range e.Landings {
+		col := rl.DarkGreen
+		if slices.Contains(e.Calls, landing) {
+			col = rl.Yellow
+		}
+		rl.DrawSphere(e.Button(landing).Add(Y.Scale(0.5)).Raylib(), 0.05, col)
+	}
+}
+
+func (l *Level) ElevatorAt(pos Vec3) *Elevator {
+	for _, e := range l.elevators {
+		if e.Contains(pos) {
+			return e
+		}
+	}
+	return nil
+}
+
+// UpdateElevator calls the car when the path leads into an elevator shaft, and sends it on
+// once aboard.
+func (p *Monster) UpdateElevator(g *Game) {
+	path := p.PathFinder.Path
+	if p.PathFinder.Idle || len(path) < 2 {
+		return
+	}
+
+	e := g.Level.ElevatorAt(path[1])
+	if e == nil {
+		return
+	}
+
+	if e.Carries(p.Position3D()) {
+		e.Call(path[1].Y)
+	} else if e.Y != math.Floor(p.Y) || !e.DoorsOpen(g) {
+		e.Call(p.Y)
+	}
+}
+
+// Ride moves the monster and its arms up or down with a moving platform.
+func (p *Monster) Ride(dy float64) {
+	p.Y += dy
+	p.YVelocity = 0
+	SetFloorFilter(p.shape, p.Y)
+
+	for _, arm := range p.Arms {
+		for _, segment := range arm.Segments {
+			segment.Y += dy
+			SetFloorFilter(segment.shape, p.Y)
+		}
+	}
+}
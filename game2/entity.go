@@ -0,0 +1,126 @@
+package game2
+
+type EntityID = uint64
+
+// Entity is anything living in the level besides the player. Exported fields are saved with
+// the game; Spawn rebuilds the physics state after creation or loading and Save writes it
+// back into exported fields.
+type Entity interface {
+	Base() *EntityBase
+	Position3D() Vec3
+
+	Spawn(g *Game)
+	Despawn(g *Game)
+	Update(g *Game)
+	Draw3D(g *Game, maxY int)
+	Save(g *Game)
+}
+
+type EntityOverlay interface {
+	DrawOverlay(g *Game)
+}
+
+type EntityBase struct {
+	ID EntityID
+
+	despawned bool
+}
+
+func (e *EntityBase) Base() *EntityBase {
+	return e
+}
+
+func (g *Game) Spawn(e Entity) EntityID {
+	g.NextEntityID++
+	e.Base().ID = g.NextEntityID
+
+	e.Spawn(g)
+	g.Entities = append(g.Entities, e)
+
+	return e.Base().ID
+}
+
+func (g *Game) Despawn(id EntityID) {
+	e := g.GetEntity(id)
+	if e == nil {
+		return
+	}
+
+	e.Base().despawned = true
+
+	if !g.entitiesLocked {
+		g.removeDespawned()
+	}
+}
+
+func (g *Game) GetEntity(id EntityID) Entity {
+	for _, e := range g.Entities {
+		if e.Base().ID == id && !e.Base().despawned {
+			return e
+		}
+	}
+	return nil
+}
+
+func (g *Game) NearestEntity(pos Vec3, maxDistance float64) Entity {
+	var nearest Entity
+	nearestDistance := maxDistance
+
+	for _, e := range g.Entities {
+		distance := e.Position3D().Distance(pos)
+		if !e.Base().despawned && distance <= nearestDistance {
+			nearest = e
+			nearestDistance = distance
+		}
+	}
+
+	return nearest
+}
+
+func EachEntity[T any](g *Game, fn func(e T)) {
+	for _, e := range g.Entities {
+		if t, ok := e.(T); ok && !e.Base().despawned {
+			fn(t)
+		}
+	}
+}
+
+func (g *Game) UpdateEntities() {
+	g.entitiesLocked = true
+	for _, e := range g.Entities {
+		if !e.Base().despawned {
+			e.Update(g)
+		}
+	}
+	g.entitiesLocked = false
+
+	g.removeDespawned()
+}
+
+func (g *Game) DrawEntities(maxY int) {
+	for _, e := range g.Entities {
+		g.SelectLightsAround(e.Position3D(), ENTITY_LIGHT_REACH)
+		e.Draw3D(g, maxY)
+	}
+}
+
+func (g *Game) DrawEntityOverlays() {
+	EachEntity(g, func(e EntityOverlay) {
+		e.DrawOverlay(g)
+	})
+}
+
+func (g *Game) removeDespawned() {
+	entities := g.Entities[:0]
+
+	for _, e := range g.Entities {
+		if e.Base().despawned {
+			e.Despawn(g)
+		} else {
+			entities = append(entities, e)
+		}
+	}
+
+	clear(g.Entities[len(entities):])
+	g.Entities = entities
+}
@@ -0,0 +1,160 @@
+package game2
+
+import (
+	"encoding/gob"
+	"fmt"
+	"image/color"
+	"math"
+	"math/rand/v2"
+
+	"github.com/gen2brain/raylib-go/raygui"
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/jakecoffman/cp"
+)
+
+// The beam angle is the outer cone in degrees, the inner cone is a fixed fraction of it.
+// Narrower beams are brighter.
+const FLASHLIGHT_BEAM = 40.0
+const FLASHLIGHT_BEAM_MIN = 15.0
+const FLASHLIGHT_BEAM_MAX = 90.0
+const FLASHLIGHT_INNER = 0.75
+const FLASHLIGHT_STRENGTH = 1.5
+const FLASHLIGHT_RANGE = 6.0
+
+// FLASHLIGHT_DRAIN is battery power per second. Below FLASHLIGHT_FLICKER_POWER the light
+// starts to flicker, more often the emptier the battery.
+const FLASHLIGHT_DRAIN = 0.5
+const FLASHLIGHT_FLICKER_POWER = 15.0
+const FLASHLIGHT_FLICKER_DIM = 0.2
+
+// A monster that sees the lit spot without seeing the player is this sure where they are.
+const FLASHLIGHT_NOTICE_CONFIDENCE = 0.6
+
+func init() {
+	gob.Register(&ItemFlashlight{})
+}
+
+type ItemFlashlight struct {
+	On   bool
+	Beam float64
+
+	strength float64
+}
+
+func NewItemFlashlight() *ItemFlashlight {
+	return &ItemFlashlight{
+		On:   true,
+		Beam: FLASHLIGHT_BEAM,
+	}
+}
+
+func (item *ItemFlashlight) Name() string {
+	return "Flashlight"
+}
+
+func (item *ItemFlashlight) Color() color.RGBA {
+	return rl.Yellow
+}
+
+// UpdateInventory toggles the light with F and draws power from a carried battery while on.
+func (item *ItemFlashlight) UpdateInventory(g *Game, p *Player) {
+	if !p.Dead && rl.IsKeyPressed(rl.KeyF) {
+		item.On = !item.On
+	}
+
+	item.strength = 0
+
+	battery := p.Battery()
+	if !item.On || battery == nil {
+		return
+	}
+
+	battery.Power = max(battery.Power-FLASHLIGHT_DRAIN*g.TimeDelta.Seconds(), 0)
+
+	item.strength = FLASHLIGHT_STRENGTH * FLASHLIGHT_BEAM / item.Beam
+	if battery.Power < FLASHLIGHT_FLICKER_POWER && rand.Float64() > battery.Power/FLASHLIGHT_FLICKER_POWER {
+		item.strength *= FLASHLIGHT_FLICKER_DIM * rand.Float64()
+	}
+}
+
+func (item *ItemFlashlight) UpdateWorld(g *Game, w *WorldItem) {
+	item.strength = 0
+}
+
+func (item *ItemFlashlight) DrawHUD(g *Game, line *LineLayout) {
+	item.On = raygui.CheckBox(line.Next(line.Height), "", item.On)
+	item.Beam = float64(raygui.Slider(line.Next(196-line.Height-40), "", fmt.Sprintf("%.0f°", item.Beam), float32(item.Beam), FLASHLIGHT_BEAM_MIN, FLASHLIGHT_BEAM_MAX))
+	line.Break(line.Height)
+}
+
+func (item *ItemFlashlight) Save(g *Game) {}
+
+// FlashlightBeam is the light cast by a flashlight that is on and powered. Angles are in
+// degrees, like the ones MainShader.LightSpot takes.
+type FlashlightBeam struct {
+	Position Vec3
+	Target   Vec3
+	Inner    float64
+	Outer    float64
+	Strength float64
+}
+
+// Flashlight returns the beam of the first carried flashlight that is lit this frame. While
+// the player looks straight down at their own feet the beam has no direction, and there is none.
+func (p *Player) Flashlight() (FlashlightBeam, bool) {
+	position := p.Position3D().Add(Y.Scale(p.Radius))
+	target := p.LookPosition.Add(Y.Scale(p.Radius))
+	if target.To2D() == position.To2D() {
+		return FlashlightBeam{}, false
+	}
+
+	for _, item := range p.Items {
+		flashlight, ok := item.(*ItemFlashlight)
+		if !ok || flashlight.strength <= 0 {
+			continue
+		}
+
+		return FlashlightBeam{
+			Position: position,
+			Target:   target,
+			Inner:    flashlight.Beam * FLASHLIGHT_INNER,
+			Outer:    flashlight.Beam,
+			Strength: flashlight.strength,
+		}, true
+	}
+	return FlashlightBeam{}, false
+}
+
+func (b FlashlightBeam) Direction() Vec2 {
+	return b.Target.To2D().Subtract(b.Position.To2D()).Normalize()
+}
+
+// Illuminates tells whether pos is inside the cone and in reach of the beam, ignoring walls.
+func (b FlashlightBeam) Illuminates(pos Vec3) bool {
+	if math.Floor(pos.Y) != math.Floor(b.Position.Y) {
+		return false
+	}
+
+	delta := pos.To2D().Subtract(b.Position.To2D())
+	distance := delta.Length()
+	if distance > FLASHLIGHT_RANGE {
+		return false
+	}
+
+	return distance == 0 || math.Acos(Clamp(b.Direction().DotProduct(delta.Scale(1/distance)), -1, 1)) <= b.Outer*rl.Deg2rad/2
+}
+
+// Spot is where the middle of the beam lands, just short of the first wall in its way or at
+// its range.
+func (b FlashlightBeam) Spot(g *Game) Vec3 {
+	from := b.Position.To2D()
+	to := from.Add(b.Direction().Scale(FLASHLIGHT_RANGE))
+
+	filter := cp.NewShapeFilter(0, Category(b.Position.Y, true, false), Category(b.Position.Y, true, false))
+	result := g.Space.SegmentQueryFirst(from.CP(), to.CP(), 0, filter)
+	if result.Shape != nil {
+		to = from.Add(to.Subtract(from).Scale(max(result.Alpha-0.02, 0)))
+	}
+
+	return Vec3From2D(to, b.Position.Y)
+}
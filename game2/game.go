@@ -29,11 +29,19 @@ type Game struct {
 
 	Day float64
 
-	Player  *Player
-	Monster *Monster
-	Space   *cp.Space
-	Level   *Level
-	Camera  Camera3D
+	Player *Player
+	Space  *cp.Space
+	Noises []Noise
+	shake  float64
+
+	lightCache lightCache
+
+	Entities       []Entity
+	NextEntityID   EntityID
+	entitiesLocked bool
+
+	Level  *Level
+	Camera Camera3D
 
 	IsStation   bool
 	RenderFlags RenderFlags
@@ -50,14 +58,19 @@ type Game struct {
 	// MainTexture              rl.RenderTexture2D
 	MainShader       *MainShader
 	TransitionShader *TransitionShader
+	ShadowShader     *ShadowShader
 
 	MainTexture              rl.RenderTexture2D
 	TransitionStationTexture rl.RenderTexture2D
 	TransitionEarthTexture   rl.RenderTexture2D
+	SunShadowTexture         rl.RenderTexture2D
+	LightMaskTexture         rl.RenderTexture2D
 
 	Textures map[string]rl.Texture2D
 
 	Models map[string]rl.Model
+
+	MonsterDefinitions map[string]*MonsterDefinition
 }
 
 type GameSave struct {
@@ -65,8 +78,10 @@ type GameSave struct {
 	TimeDelta              time.Duration
 	TimePhysicsAccumulator time.Duration
 
-	Player  PlayerSave
-	Monster *Monster
+	Player       PlayerSave
+	Entities     []Entity
+	NextEntityID EntityID
+	Physics      PhysicsSnapshot
 
 	Level       Level
 	RenderFlags RenderFlags
@@ -76,13 +91,19 @@ type GameSave struct {
 }
 
 func (g *Game) ToSave() GameSave {
+	for _, e := range g.Entities {
+		e.Save(g)
+	}
+
 	return GameSave{
 		Time:                   g.Time,
 		TimeDelta:              g.TimeDelta,
 		TimePhysicsAccumulator: g.TimePhysicsAccumulator,
 		Level:                  *g.Level,
 		Player:                 g.Player.ToSave(g),
-		Monster:                g.Monster.ToSave(g),
+		Entities:               g.Entities,
+		NextEntityID:           g.NextEntityID,
+		Physics:                g.PhysicsSnapshot(),
 		RenderFlags:            g.RenderFlags,
 		EditorEnabled:          g.EditorEnabled,
 		Editor:                 g.Editor,
@@ -97,13 +118,16 @@ func (g *Game) Update(dt time.Duration) {
 	g.Day += dt.Seconds() / 100
 	// g.Day = 0.5
 
+	g.pruneNoises()
+
 	for g.TimePhysicsAccumulator >= PHYSICS_TICKRATE {
 		g.Space.Step(PHYSICS_TICKRATE.Seconds())
 		g.TimePhysicsAccumulator -= PHYSICS_TICKRATE
 	}
 
-	g.Camera.Position = g.Player.Position3D().Add(NewVec3(0, 8, -3).Normalize().Scale(10))
-	g.Camera.Target = g.Player.Position3D()
+	shake := g.UpdateShake()
+	g.Camera.Position = g.Player.Position3D().Add(NewVec3(0, 8, -3).Normalize().Scale(10)).Add(shake)
+	g.Camera.Target = g.Player.Position3D().Add(shake)
 
 	mousePos := rl.GetMousePosition()
 	g.MousePosition = Vec2FromRaylib(mousePos)
@@ -114,10 +138,12 @@ func (g *Game) Update(dt time.Duration) {
 	g.MouseRayDirection = Vec3FromRaylib(mouseRay.Direction)
 
 	g.Player.Update(g)
+	g.UpdateDoorNoise()
+	g.UpdateLocks()
+	g.UpdatePower()
+	g.UpdateLightCache()
 
-	if g.Monster != nil {
-		g.Monster.Update(g)
-	}
+	g.UpdateEntities()
 
 	cellWakeX := 8
 	cellWakeZ := 8
@@ -179,32 +205,44 @@ func (save GameSave) Load() *Game {
 		MainTexture:              rl.LoadRenderTexture(int32(screenWidth/DOWNSCALE), int32(screenHeight/DOWNSCALE)),
 		TransitionEarthTexture:   rl.LoadRenderTexture(int32(screenWidth/DOWNSCALE), int32(screenHeight/DOWNSCALE)),
 		TransitionStationTexture: rl.LoadRenderTexture(int32(screenWidth/DOWNSCALE), int32(screenHeight/DOWNSCALE)),
+		SunShadowTexture:         rl.LoadRenderTexture(SUN_SHADOW_RESOLUTION, SUN_SHADOW_RESOLUTION),
+		LightMaskTexture:         rl.LoadRenderTexture(LIGHT_MASK_GRID*LIGHT_MASK_TILE, LIGHT_MASK_GRID*LIGHT_MASK_TILE),
 
-		Models: map[string]rl.Model{},
+		Models:             map[string]rl.Model{},
+		MonsterDefinitions: map[string]*MonsterDefinition{},
 
 		Textures:         map[string]rl.Texture2D{},
 		MainShader:       NewShader(&MainShader{}, "./glsl330/lighting.vs", "./glsl330/lighting.fs"),
 		TransitionShader: NewShader(&TransitionShader{}, "", "./glsl330/fade.fs"),
+		ShadowShader:     NewShader(&ShadowShader{}, "./glsl330/shadow.vs", "./glsl330/shadow.fs"),
 	}
 
 	rl.SetTextureFilter(g.TransitionEarthTexture.Texture, rl.FilterPoint)
 	rl.SetTextureFilter(g.TransitionStationTexture.Texture, rl.FilterPoint)
 
 	g.Space = cp.NewSpace()
+	g.AddCollisionHandlers()
 	g.Level = save.Level.Init()
 
 	g.LoadModel("wallDebug", "./models/wallx.glb", g.MainShader, nil)
 	g.LoadModel("wall", "./models/wallx.glb", g.MainShader, &g.Tileset.Texture)
 	g.LoadModel("stair", "./models/stair.glb", g.MainShader, &g.Tileset.Texture)
 	g.LoadModel("door", "./models/door.glb", g.MainShader, &g.Tileset.Texture)
+	g.LoadModel("ladder", "./models/ladder.glb", g.MainShader, &g.Tileset.Texture)
+	g.LoadModel("window", "./models/window.glb", g.MainShader, &g.Tileset.Texture)
+	g.LoadModel("window_glass", "./models/window_glass.glb", g.MainShader, nil)
 	g.LoadModel("monster_arm_segment", "./models/monster/monster_arm_segment.glb", g.MainShader, &g.Tileset.Texture)
 	g.LoadModel("monster_body", "./models/monster/monster_body.glb", g.MainShader, &g.Tileset.Texture)
 
 	save.Player.Load(g)
-	if save.Monster == nil {
-		save.Monster = &Monster{SavePosition: NewVec2(0, 0)}
+
+	g.NextEntityID = save.NextEntityID
+	for _, e := range save.Entities {
+		e.Spawn(g)
+		g.Entities = append(g.Entities, e)
 	}
-	save.Monster.Load(g)
+
+	save.Physics.Apply(g)
 
 	return g
 }
@@ -215,11 +253,15 @@ func NewGameSave() GameSave {
 		TimeDelta:              0,
 		TimePhysicsAccumulator: 0,
 		Player: PlayerSave{
-			Position: NewVec2(0, 0),
+			Body:  BodyState{Position: NewVec2(0, 0)},
+			Items: DefaultPlayerItems(),
 		},
-		Monster: &Monster{
-			SavePosition: NewVec2(0, 0),
+		Entities: []Entity{
+			&Monster{
+				EntityBase: EntityBase{ID: 1},
+			},
 		},
+		NextEntityID:  1,
 		EditorEnabled: false,
 		Editor:        NewEditor(),
 		Level:         Level{},
@@ -228,9 +270,19 @@ func NewGameSave() GameSave {
 	return gameSave
 }
 
+// DrawMaxY is the highest floor drawn while playing, a few above the player's.
+func (g *Game) DrawMaxY() int {
+	return int(g.Player.Y) + 4
+}
+
 func (g *Game) Draw() {
 	g.Player.RenderViewTexture(g)
 
+	maxY := g.DrawMaxY()
+	sunDirection, sunColor, sunStrength := g.SunLight()
+	g.RenderSunShadow(sunDirection, maxY)
+	g.RenderLightMasks()
+
 	BeginTextureMode(g.MainTexture, func() {
 		rl.ClearBackground(color.RGBA{})
 
@@ -245,28 +297,19 @@ func (g *Game) Draw() {
 				g.MainShader.FullBright.Set(0)
 			}
 
-			g.MainShader.Ambient.SetColor(color.RGBA{50, 50, 50, 255})
+			g.MainShader.Ambient.SetColor(AMBIENT)
+			g.MainShader.ResetLights()
 
-			if !g.IsStation {
+			g.MainShader.LightDirectional(sunDirection, sunColor, sunStrength)
 
-				hour := math.Mod(g.Day, 1) * 24
-				day := c(hour-HOUR_MORNING) - c(hour-HOUR_NIGHT)
-				transitionColor := 1 + ((c(2*(hour-HOUR_MORNING-HOURS_TRANSITION/2)) - c(2*(hour-HOUR_MORNING+HOURS_TRANSITION/2))) + (c(2*(hour-HOUR_NIGHT-HOURS_TRANSITION/2)) - c(2*(hour-HOUR_NIGHT+HOURS_TRANSITION/2))))
-				transitionAngle := 1 + ((c((hour - HOUR_MORNING - HOURS_TRANSITION/2)) - c((hour - HOUR_MORNING + HOURS_TRANSITION/2))) + (c((hour - HOUR_NIGHT - HOURS_TRANSITION/2)) - c((hour - HOUR_NIGHT + HOURS_TRANSITION/2))))
-
-				sunColor := DAWN.Lerp(NIGHT.Lerp(DAY, (day)), (transitionColor))
-
-				g.MainShader.LightDirectional(NewVec3((1-transitionAngle), (1-day*2), 0).Normalize(), rl.ColorFromHSV(float32(sunColor.X), float32(sunColor.Y), float32(sunColor.Z)), 1)
-
-			} else {
-				g.MainShader.LightDirectional(NewVec3(0, -1, 0).Normalize(), rl.White, 0.25)
+			if beam, ok := g.Player.Flashlight(); ok {
+				g.MainShader.LightSpot(beam.Position, beam.Target, beam.Inner, beam.Outer, rl.White, beam.Strength)
 			}
 
-			g.MainShader.LightSpot(g.Player.Position3D().Add(Y.Scale(g.Player.Radius)), g.Player.LookPosition.Add(Y.Scale(g.Player.Radius)), 30, 40, rl.White, 1.5)
-
+			g.MainShader.KeepLights()
 			g.MainShader.UpdateValues()
 
-			g.Draw3D(int(g.Player.Y) + 4)
+			g.Draw3D(maxY)
 		})
 
 	})
@@ -318,6 +361,8 @@ func (g *Game) Draw() {
 		0,
 		rl.White,
 	)
+
+	g.Player.DrawHUD(g)
 }
 
 var NIGHT = Vec3{-115, 0.3, .1}
@@ -331,9 +376,27 @@ func c(x float64) float64 {
 	return (1 + math.Tanh(x)) / 2
 }
 
+// SunLight follows the day cycle; stations get a dim light from straight above instead.
+func (g *Game) SunLight() (Vec3, rl.Color, float64) {
+	if g.IsStation {
+		return NewVec3(0, -1, 0), rl.White, 0.25
+	}
+
+	hour := math.Mod(g.Day, 1) * 24
+	day := c(hour-HOUR_MORNING) - c(hour-HOUR_NIGHT)
+	transitionColor := 1 + ((c(2*(hour-HOUR_MORNING-HOURS_TRANSITION/2)) - c(2*(hour-HOUR_MORNING+HOURS_TRANSITION/2))) + (c(2*(hour-HOUR_NIGHT-HOURS_TRANSITION/2)) - c(2*(hour-HOUR_NIGHT+HOURS_TRANSITION/2))))
+	transitionAngle := 1 + ((c((hour - HOUR_MORNING - HOURS_TRANSITION/2)) - c((hour - HOUR_MORNING + HOURS_TRANSITION/2))) + (c((hour - HOUR_NIGHT - HOURS_TRANSITION/2)) - c((hour - HOUR_NIGHT + HOURS_TRANSITION/2))))
+
+	sunColor := DAWN.Lerp(NIGHT.Lerp(DAY, (day)), (transitionColor))
+
+	return NewVec3((1 - transitionAngle), (1 - day*2), 0).Normalize(), rl.ColorFromHSV(float32(sunColor.X), float32(sunColor.Y), float32(sunColor.Z)), 1
+}
+
 func (g *Game) Draw3D(maxY int) {
 
 	g.Level.Draw(g, maxY)
+	g.Level.Power.Draw(g, maxY)
+	g.Level.DrawLights(g, maxY)
 
 	/*
 		{
@@ -360,10 +423,9 @@ func (g *Game) Draw3D(maxY int) {
 
 		}*/
 
+	g.SelectLightsAround(g.Player.Position3D(), g.Player.Radius)
 	g.Player.Draw(g)
-	if g.Monster != nil {
-		g.Monster.Draw3D(g, maxY)
-	}
+	g.DrawEntities(maxY)
 }
 
 func LoadSaveFromFile(path string, save *GameSave) error {
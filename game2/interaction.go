@@ -0,0 +1,139 @@
+package game2
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gen2brain/raylib-go/raygui"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// The player uses whatever lies within INTERACT_RADIUS of where they look, as long as it is
+// within INTERACT_REACH of them.
+const INTERACT_REACH = 1.5
+const INTERACT_RADIUS = 0.6
+const INTERACT_DOOR_IMPULSE = 0.3
+
+// Interactable is anything the player can use with E: doors in the level, and entities or
+// parts of them.
+type Interactable interface {
+	InteractPosition() Vec3
+	InteractPrompt(g *Game, p *Player) string
+	Interact(g *Game, p *Player)
+}
+
+// InteractableSource is an entity offering several things to use, like the call buttons of
+// an elevator. Entities with just one implement Interactable themselves.
+type InteractableSource interface {
+	Interactables(g *Game) []Interactable
+}
+
+func (g *Game) Interactables() []Interactable {
+	var targets []Interactable
+
+	for _, ref := range g.Level.doors {
+		targets = append(targets, ref)
+	}
+	targets = append(targets, g.Level.Power.Interactables()...)
+
+	for _, e := range g.Entities {
+		if e.Base().despawned {
+			continue
+		}
+		switch e := e.(type) {
+		case Interactable:
+			targets = append(targets, e)
+		case InteractableSource:
+			targets = append(targets, e.Interactables(g)...)
+		}
+	}
+
+	return targets
+}
+
+// InteractTarget finds what the player is looking at and can reach.
+func (g *Game) InteractTarget(p *Player) Interactable {
+	playerPos := p.Position3D()
+
+	var nearest Interactable
+	nearestDistance := INTERACT_RADIUS
+
+	for _, target := range g.Interactables() {
+		pos := target.InteractPosition()
+
+		if math.Floor(pos.Y) != math.Floor(playerPos.Y) || pos.To2D().Distance(playerPos.To2D()) > INTERACT_REACH {
+			continue
+		}
+		if distance := pos.To2D().Distance(p.LookPosition.To2D()); distance <= nearestDistance {
+			nearest = target
+			nearestDistance = distance
+		}
+	}
+
+	return nearest
+}
+
+func (p *Player) UpdateInteraction(g *Game) {
+	p.interactTarget = nil
+	if p.Dead {
+		return
+	}
+
+	p.interactTarget = g.InteractTarget(p)
+
+	if p.interactTarget != nil && rl.IsKeyPressed(rl.KeyE) {
+		p.interactTarget.Interact(g, p)
+	}
+}
+
+func (p *Player) DrawInteraction(g *Game) {
+	if p.interactTarget == nil {
+		return
+	}
+	rl.DrawCubeWiresV(p.interactTarget.InteractPosition().Raylib(), XYZ.Scale(0.3).Raylib(), rl.Yellow)
+}
+
+func (p *Player) DrawInteractionHUD(g *Game) {
+	if p.interactTarget == nil {
+		return
+	}
+
+	width := float64(300)
+	size := float64(24)
+	line := NewLineLayout((float64(rl.GetRenderWidth())-width)/2, float64(rl.GetRenderHeight())-size*3, size)
+
+	raygui.Label(line.Next(width), raygui.IconText(raygui.ICON_HAND_POINTER, "[E] "+p.interactTarget.InteractPrompt(g, p)))
+}
+
+func (r FaceRef) InteractPosition() Vec3 {
+	pos := Vec2FromCP(r.Face().body.Position())
+	return Vec3From2D(pos, r.Cell.Position.Y+0.5)
+}
+
+func (r FaceRef) InteractPrompt(g *Game, p *Player) string {
+	face := r.Face()
+
+	switch {
+	case face.stall != nil:
+		return "No power"
+	case face.CanUnlock(p.KeycardLevel()):
+		return "Push door"
+	case face.Lock == LockKeycard:
+		return fmt.Sprintf("Requires keycard level %v", face.LockLevel)
+	default:
+		return "Locked"
+	}
+}
+
+// Interact swings the door away from the player.
+func (r FaceRef) Interact(g *Game, p *Player) {
+	face := r.Face()
+	if face.stall != nil || !face.CanUnlock(p.KeycardLevel()) {
+		return
+	}
+
+	center := face.body.Position()
+	push := center.Sub(p.body.Position()).Normalize().Mult(INTERACT_DOOR_IMPULSE)
+	face.body.ApplyImpulseAtWorldPoint(push, center)
+	face.pushedBy = GroupPlayer
+}
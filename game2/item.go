@@ -0,0 +1,180 @@
+package game2
+
+import (
+	"encoding/gob"
+	"image/color"
+	"math"
+	"slices"
+
+	"github.com/gen2brain/raylib-go/raygui"
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/jakecoffman/cp"
+)
+
+const ITEM_RADIUS = 0.12
+const ITEM_DAMPING = 0.8
+const ITEM_DROP_DISTANCE = 0.4
+
+func init() {
+	gob.Register(&WorldItem{})
+}
+
+// Item is anything the player can carry. Items are saved with gob, so every implementation
+// registers itself and keeps what it needs in exported fields, writing it back in Save.
+type Item interface {
+	Name() string
+
+	// UpdateInventory runs every frame while the item is carried, UpdateWorld while it lies
+	// in the level.
+	UpdateInventory(g *Game, p *Player)
+	UpdateWorld(g *Game, w *WorldItem)
+
+	DrawHUD(g *Game, line *LineLayout)
+	Save(g *Game)
+}
+
+type ItemColor interface {
+	Color() color.RGBA
+}
+
+// WorldItem is an item lying in the level as a small physics body.
+type WorldItem struct {
+	EntityBase
+
+	Item      Item
+	Y         float64
+	YVelocity float64
+	Body      BodyState
+
+	body  *cp.Body
+	shape *cp.Shape
+}
+
+func (g *Game) SpawnItem(item Item, position Vec3) *WorldItem {
+	w := &WorldItem{
+		Item: item,
+		Y:    position.Y,
+		Body: BodyState{Position: position.To2D()},
+	}
+	g.Spawn(w)
+	return w
+}
+
+func (w *WorldItem) Position3D() Vec3 {
+	return Vec3From2D(Vec2FromCP(w.body.Position()), w.Y)
+}
+
+func (w *WorldItem) Spawn(g *Game) {
+	mass := ITEM_RADIUS * ITEM_RADIUS
+	w.body = g.Space.AddBody(cp.NewBody(mass, cp.MomentForCircle(mass, 0, ITEM_RADIUS, cp.Vector{})))
+	w.Body.Apply(w.body)
+
+	w.shape = g.Space.AddShape(cp.NewCircle(w.body, ITEM_RADIUS, cp.Vector{}))
+	w.shape.SetElasticity(0)
+	w.shape.SetFriction(0.9)
+	w.shape.UserData = w
+	SetFloorFilter(w.shape, w.Y)
+}
+
+func (w *WorldItem) Despawn(g *Game) {
+	RemoveBody(g.Space, w.body)
+}
+
+func (w *WorldItem) Save(g *Game) {
+	w.Body = NewBodyState(w.body)
+	w.Item.Save(g)
+}
+
+func (w *WorldItem) Update(g *Game) {
+	velocity := w.body.Velocity().Mult(ITEM_DAMPING)
+	w.body.SetVelocity(velocity.X, velocity.Y)
+	w.body.SetAngularVelocity(w.body.AngularVelocity() * ITEM_DAMPING)
+
+	w.Y, w.YVelocity = UpdatePhysicsY(g, w.shape, w.Y, w.YVelocity)
+
+	w.Item.UpdateWorld(g, w)
+}
+
+func (w *WorldItem) Draw3D(g *Game, maxY int) {
+	if math.Floor(w.Y) > float64(maxY) {
+		return
+	}
+
+	col := rl.Gold
+	if c, ok := w.Item.(ItemColor); ok {
+		col = c.Color()
+	}
+
+	center := w.Position3D().Add(Y.Scale(ITEM_RADIUS / 2))
+	size := NewVec3(ITEM_RADIUS*1.5, ITEM_RADIUS, ITEM_RADIUS*1.5)
+	rl.DrawCubeV(center.Raylib(), size.Raylib(), col)
+}
+
+func (w *WorldItem) InteractPosition() Vec3 {
+	return w.Position3D().Add(Y.Scale(ITEM_RADIUS / 2))
+}
+
+func (w *WorldItem) InteractPrompt(g *Game, p *Player) string {
+	return "Pick up " + w.Item.Name()
+}
+
+func (w *WorldItem) Interact(g *Game, p *Player) {
+	p.TakeItem(g, w)
+}
+
+// UpdateItems drops the item picked in the HUD last frame, then updates the ones carried.
+func (p *Player) UpdateItems(g *Game) {
+	if p.dropItem != nil {
+		p.DropItem(g, p.dropItem)
+		p.dropItem = nil
+	}
+
+	for _, item := range p.Items {
+		item.UpdateInventory(g, p)
+	}
+}
+
+func (p *Player) TakeItem(g *Game, w *WorldItem) {
+	p.Items = append(p.Items, w.Item)
+	g.Despawn(w.ID)
+}
+
+func (p *Player) RemoveItem(target Item) {
+	p.Items = slices.DeleteFunc(p.Items, func(item Item) bool {
+		return item == target
+	})
+}
+
+// DropItem puts the item down in front of the player.
+func (p *Player) DropItem(g *Game, item Item) {
+	p.RemoveItem(item)
+
+	direction := p.LookPosition.Subtract(p.Position3D()).To2D().Normalize()
+	position := p.Position3D().Add(Vec3From2D(direction.Scale(ITEM_DROP_DISTANCE), 0))
+
+	g.SpawnItem(item, position)
+	g.EmitNoise(position, ITEM_DROP_LOUDNESS, NoiseImpact, GroupPlayer)
+}
+
+func (p *Player) DrawInventoryHUD(g *Game) {
+	width := float64(220)
+	size := float64(24)
+	line := NewLineLayout(float64(rl.GetRenderWidth())-width-10, 10, size)
+
+	if len(p.Items) == 0 {
+		return
+	}
+
+	raygui.Label(line.Next(width), raygui.IconText(raygui.ICON_BOX, "Inventory"))
+	line.Break(size)
+
+	for _, item := range p.Items {
+		raygui.Label(line.Next(width-size), item.Name())
+		if raygui.Button(line.Next(size), raygui.IconText(raygui.ICON_CROSS_SMALL, "")) {
+			p.dropItem = item
+		}
+		line.Break(size)
+
+		item.DrawHUD(g, line)
+	}
+}
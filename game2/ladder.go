@@ -0,0 +1,107 @@
+package game2
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const LADDER_CLIMB_SPEED = 1.5
+const MONSTER_CLIMB_SPEED = 1.0
+
+// A ladder cell leads up into the cell above it. The open cell at the top of a shaft is a
+// landing that can be stood on, so the shaft doesn't swallow whoever steps off the ladder.
+func (c *Cell) IsLadderLanding() bool {
+	if c.Ground.Type != GroundEmpty || c.Position.Y <= 0 {
+		return false
+	}
+	return c.level.GetCell(c.Position.Subtract(Y)).Ground.Type == GroundLadder
+}
+
+// LadderSpan returns the heights of the bottom of the ladder shaft at pos and of its landing.
+func (l *Level) LadderSpan(pos Vec3) (float64, float64, bool) {
+	cell := l.GetCell(pos.Floor())
+
+	if cell.IsLadderLanding() {
+		cell = l.GetCell(cell.Position.Subtract(Y))
+	}
+	if cell.Ground.Type != GroundLadder {
+		return 0, 0, false
+	}
+
+	bottom := cell
+	for bottom.Position.Y > 0 {
+		below := l.GetCell(bottom.Position.Subtract(Y))
+		if below.Ground.Type != GroundLadder {
+			break
+		}
+		bottom = below
+	}
+
+	top := cell
+	for top.Ground.Type == GroundLadder {
+		top = l.GetCell(top.Position.Add(Y))
+	}
+
+	return bottom.Position.Y, top.Position.Y, true
+}
+
+// UpdateClimb moves the player along a ladder with W and S. Climbing starts when pushing
+// towards the other end of the shaft and ends on reaching either end.
+func (p *Player) UpdateClimb(g *Game) bool {
+	bottom, top, ok := g.Level.LadderSpan(p.Position3D())
+
+	if !ok || p.Dead {
+		p.Climbing = false
+		return false
+	}
+
+	up := rl.IsKeyDown(rl.KeyW)
+	down := rl.IsKeyDown(rl.KeyS)
+
+	if !p.Climbing && !(up && p.Y < top) && !(down && p.Y > bottom) {
+		return false
+	}
+	p.Climbing = true
+
+	direction := 0.0
+	if up {
+		direction++
+	}
+	if down {
+		direction--
+	}
+
+	p.Y = Clamp(p.Y+direction*LADDER_CLIMB_SPEED*g.TimeDelta.Seconds(), bottom, top)
+	p.YVelocity = 0
+
+	if (up && p.Y == top) || (down && p.Y == bottom) {
+		p.Climbing = false
+	}
+
+	return true
+}
+
+// UpdateClimb moves the monster up or down a ladder when its path leads straight up or
+// down the shaft it is in.
+func (p *Monster) UpdateClimb(g *Game) bool {
+	path := p.PathFinder.Path
+	if p.PathFinder.Idle || len(path) < 2 || path[0].X != path[1].X || path[0].Z != path[1].Z {
+		return false
+	}
+
+	bottom, top, ok := g.Level.LadderSpan(p.Position3D())
+	if !ok {
+		return false
+	}
+
+	step := MONSTER_CLIMB_SPEED * g.TimeDelta.Seconds()
+	target := Clamp(path[1].Y, bottom, top)
+
+	if p.Y < target {
+		p.Y = min(p.Y+step, target)
+	} else {
+		p.Y = max(p.Y-step, target)
+	}
+	p.YVelocity = 0
+
+	return true
+}
@@ -14,8 +14,12 @@ type Chunk = [CHUNK_WIDTH][CHUNK_WIDTH][CHUNK_HEIGHT]Cell
 
 type Level struct {
 	Chunks map[Vec2]*Chunk
+	Power  PowerNetwork
+	Lights []PlacedLight
 
-	refs map[Vec3]*Cell
+	refs      map[Vec3]*Cell
+	doors     []FaceRef
+	elevators []*Elevator
 }
 
 func (l *Level) Init() *Level {
@@ -67,21 +71,41 @@ func (l *Level) GetCell(pos Vec3) *Cell {
 	return ref
 }
 
-func (l *Level) Draw(g *Game, maxY int) {
-	for _, chunk := range l.Chunks {
+// PeekCell is GetCell for positions that may lie outside the level. It returns nil instead
+// of creating a chunk.
+func (l *Level) PeekCell(pos Vec3) *Cell {
+	if pos.Y < 0 || pos.Y >= float64(CHUNK_HEIGHT) {
+		return nil
+	}
 
-		for x := range CHUNK_WIDTH {
-			for z := range CHUNK_WIDTH {
-				for y := range CHUNK_HEIGHT {
-					cell := &chunk[x][z][y]
+	chunkPos := NewVec2(pos.X/float64(CHUNK_WIDTH), pos.Z/float64(CHUNK_WIDTH)).Floor()
+	if l.Chunks[chunkPos] == nil {
+		return nil
+	}
+	return l.GetCell(pos.Floor())
+}
 
-					if y > maxY {
-						continue
-					}
+func (l *Level) Draw(g *Game, maxY int) {
+	for chunkPos, chunk := range l.Chunks {
+		aa := NewVec3(chunkPos.X*float64(CHUNK_WIDTH), 0, chunkPos.Y*float64(CHUNK_WIDTH))
+		g.SelectLights(aa, aa.AddXYZ(float64(CHUNK_WIDTH), float64(maxY+1), float64(CHUNK_WIDTH)))
 
-					cell.Draw(g)
+		l.DrawChunk(g, chunk, maxY)
+	}
+}
 
+func (l *Level) DrawChunk(g *Game, chunk *Chunk, maxY int) {
+	for x := range CHUNK_WIDTH {
+		for z := range CHUNK_WIDTH {
+			for y := range CHUNK_HEIGHT {
+				cell := &chunk[x][z][y]
+
+				if y > maxY {
+					continue
 				}
+
+				cell.Draw(g)
+
 			}
 		}
 	}
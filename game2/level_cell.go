@@ -1,7 +1,10 @@
 package game2
 
 import (
+	"image/color"
 	"math"
+	"slices"
+	"time"
 
 	"github.com/beefsack/go-astar"
 	rl "github.com/gen2brain/raylib-go/raylib"
@@ -67,6 +70,7 @@ const (
 	FaceEmpty = FaceType(iota)
 	FaceDoor
 	FaceWall
+	FaceWindow
 )
 
 type GroundType = uint8
@@ -75,6 +79,7 @@ const (
 	GroundEmpty = GroundType(iota)
 	GroundFloor
 	GroundStair
+	GroundLadder
 )
 
 type Ground struct {
@@ -82,15 +87,58 @@ type Ground struct {
 	TileX          int
 	TileY          int
 	Type           GroundType
+	Checkpoint     bool
 }
 
 type Face struct {
-	Type  FaceType
-	TileX int
-	TileY int
+	Type      FaceType
+	TileX     int
+	TileY     int
+	Damage    float64
+	Breakable bool
+	Lock      LockState
+	LockLevel int
+
+	body      *cp.Body
+	shape     *cp.Shape
+	lock      *cp.Constraint
+	stall     *cp.Constraint
+	lastNoise time.Duration
+	pushedBy  uint
+}
+
+// Saved is the face as the level file keeps it, without any of its physics.
+func (face *Face) Saved() Face {
+	return Face{
+		Type:      face.Type,
+		TileX:     face.TileX,
+		TileY:     face.TileY,
+		Damage:    face.Damage,
+		Breakable: face.Breakable,
+		Lock:      face.Lock,
+		LockLevel: face.LockLevel,
+	}
+}
+
+func (face *Face) BlocksPath() bool {
+	return face.Type == FaceWall || face.Type == FaceWindow
+}
+
+func (face *Face) DrawWindow(g *Game, center Vec3, rotationAxis Vec3, rotationDegrees float32) {
+	rl.DrawModelEx(g.GetModel("window"), center.Raylib(), rotationAxis.Raylib(), rotationDegrees, XYZ.Raylib(), rl.White)
+
+	rl.BeginBlendMode(rl.BlendAlpha)
+	rl.DrawModelEx(g.GetModel("window_glass"), center.Raylib(), rotationAxis.Raylib(), rotationDegrees, XYZ.Raylib(), color.RGBA{180, 220, 255, 80})
+	rl.EndBlendMode()
+}
 
-	body  *cp.Body
-	shape *cp.Shape
+type FaceRef struct {
+	Cell  *Cell
+	Index FaceIndex
+}
+
+func (r FaceRef) Face() *Face {
+	return &r.Cell.Faces[r.Index]
 }
 
 type Cell struct {
@@ -118,6 +166,19 @@ func (c *Cell) Wake(g *Game) {
 				shape.Filter.Categories = Category(c.Position.Y, true, false)
 				shape.Filter.Mask = Category(c.Position.Y, true, true)
 
+				face.shape = g.Space.AddShape(shape)
+			case FaceWindow:
+				// Windows only collide with entities, so sight and light queries against the
+				// level pass straight through them.
+				face.body = g.Space.StaticBody
+				shape := cp.NewPolyShape(face.body, 4, WALL_VERTS[FACE], transform, 0)
+
+				shape.Filter.Group = GroupStatic
+				shape.Filter.Categories = Category(c.Position.Y, false, true)
+				shape.Filter.Mask = Category(c.Position.Y, true, true)
+				shape.SetCollisionType(CollisionWindow)
+				shape.UserData = FaceRef{c, FACE}
+
 				face.shape = g.Space.AddShape(shape)
 			case FaceDoor:
 				position := c.Position.AddXYZ(0.5, 0, 0.5).Subtract(FACE_DIRECTION[FACE_OPPOSITE[FACE]].Scale((1 - WALL_WIDTH) / 2))
@@ -151,6 +212,8 @@ func (c *Cell) Wake(g *Game) {
 				g.Space.AddConstraint(rotaryLimit)
 				g.Space.AddConstraint(pivot)
 				g.Space.AddConstraint(dampedSpring)
+
+				g.Level.doors = append(g.Level.doors, FaceRef{c, FACE})
 			}
 		}
 
@@ -164,6 +227,10 @@ func (face *Face) Draw(g *Game, cellPos Vec3, rotationAxis Vec3, rotationDegrees
 		g.MainShader.UVClamp.Set(aa.X, aa.Y, bb.X, bb.Y)
 		center := cellPos
 		rl.DrawModelEx(g.GetModel("wall"), center.Raylib(), rotationAxis.Raylib(), rotationDegrees, XYZ.Raylib(), rl.White)
+	case FaceWindow:
+		aa, bb := g.Tileset.GetAABB(face.TileX, face.TileY)
+		g.MainShader.UVClamp.Set(aa.X, aa.Y, bb.X, bb.Y)
+		face.DrawWindow(g, cellPos, rotationAxis, rotationDegrees)
 	case FaceDoor:
 		if face.body != nil {
 			aa, bb := g.Tileset.GetAABB(face.TileX, face.TileY)
@@ -188,35 +255,79 @@ func (gr *Ground) Draw(g *Game, cellPos Vec3) {
 		g.MainShader.UVClamp.Set(aa.X, aa.Y, bb.X, bb.Y)
 		center := cellPos.AddXYZ(0.5, 0, 0.5)
 		rl.DrawModelEx(g.GetModel("stair"), center.Raylib(), Y.Negate().Raylib(), float32(FACE_DEGREE[gr.StairDirection]-90), XYZ.Scale(0.99).Raylib(), rl.White)
+	} else if gr.Type == GroundLadder {
+		aa, bb := g.Tileset.GetAABB(gr.TileX, gr.TileY)
+		g.MainShader.UVClamp.Set(aa.X, aa.Y, bb.X, bb.Y)
+		center := cellPos.AddXYZ(0.5, 0.5, 0.5)
+		rl.DrawModelEx(g.GetModel("ladder"), center.Raylib(), Y.Negate().Raylib(), float32(FACE_DEGREE[gr.StairDirection]), XYZ.Raylib(), rl.White)
 	}
 }
 
+// BreakFace knocks a door or window out of its frame, leaving an open doorway.
+func (l *Level) BreakFace(g *Game, ref FaceRef) {
+	face := ref.Face()
+	l.ReplaceFace(g, ref, Face{TileX: face.TileX, TileY: face.TileY})
+}
+
+// ReplaceFace takes the old face's body and shapes out of the space and forgets it as a door
+// before putting the new one in its place. The new face gets its own physics once the cell
+// wakes again.
+func (l *Level) ReplaceFace(g *Game, ref FaceRef, replacement Face) {
+	face := ref.Face()
+
+	switch {
+	case face.body == g.Space.StaticBody:
+		g.Space.RemoveShape(face.shape)
+	case face.body != nil:
+		RemoveBody(g.Space, face.body)
+	}
+	*face = replacement.Saved()
+
+	l.doors = slices.DeleteFunc(l.doors, func(door FaceRef) bool {
+		return door == ref
+	})
+}
+
 func (c *Cell) PathNeighbors() []astar.Pather {
+	cells := c.Neighbors(c.level.GetCell)
+	neighbors := make([]astar.Pather, len(cells))
+	for i, cell := range cells {
+		neighbors[i] = cell
+	}
+	return neighbors
+}
+
+// Neighbors are the cells a path may step to from c, looked up with cellAt. Positions where
+// cellAt has no cell are left out, so a lookup like PeekCell keeps to the loaded level.
+func (c *Cell) Neighbors(cellAt func(pos Vec3) *Cell) []*Cell {
+	neighbors := make([]*Cell, 0)
+	add := func(pos Vec3) {
+		if cell := cellAt(pos); cell != nil {
+			neighbors = append(neighbors, cell)
+		}
+	}
 
 	switch c.Ground.Type {
 	case GroundEmpty:
-		return []astar.Pather{}
-	case GroundStair:
-		prevPos := c.Position.Add(FACE_DIRECTION[FACE_OPPOSITE[c.Ground.StairDirection]])
-		nextPos := c.Position.Add(FACE_DIRECTION[c.Ground.StairDirection]).Add(Y)
-		return []astar.Pather{
-			c.level.GetCell(prevPos),
-			c.level.GetCell(nextPos),
+		if !c.IsLadderLanding() && !c.IsElevatorLanding() {
+			return neighbors
 		}
+	case GroundStair:
+		add(c.Position.Add(FACE_DIRECTION[FACE_OPPOSITE[c.Ground.StairDirection]]))
+		add(c.Position.Add(FACE_DIRECTION[c.Ground.StairDirection]).Add(Y))
+		return neighbors
 	}
 
-	neighbors := make([]astar.Pather, 0)
-
 	for FACE := range FACES {
 		face := &c.Faces[FACE]
 
-		if face.Type == FaceWall {
+		if face.BlocksPath() {
 			continue
 		}
 
-		next := c.level.GetCell(c.Position.Add(FACE_DIRECTION[FACE]))
+		next := cellAt(c.Position.Add(FACE_DIRECTION[FACE]))
 
-		if next.Faces[FACE_OPPOSITE[FACE]].Type == FaceWall {
+		if next == nil || next.Faces[FACE_OPPOSITE[FACE]].BlocksPath() {
 			continue
 		}
 
@@ -226,22 +337,87 @@ func (c *Cell) PathNeighbors() []astar.Pather {
 		neighbors = append(neighbors, next)
 
 		if c.Position.Y > 0 {
-			nextBelow := c.level.GetCell(c.Position.Add(FACE_DIRECTION[FACE]).Subtract(Y))
+			nextBelow := cellAt(c.Position.Add(FACE_DIRECTION[FACE]).Subtract(Y))
 
-			if nextBelow.Ground.Type == GroundStair && nextBelow.Ground.StairDirection == FACE_OPPOSITE[FACE] {
+			if nextBelow != nil && nextBelow.Ground.Type == GroundStair && nextBelow.Ground.StairDirection == FACE_OPPOSITE[FACE] {
 				neighbors = append(neighbors, nextBelow)
 			}
 		}
 
 	}
 
+	if c.Ground.Type == GroundLadder {
+		add(c.Position.Add(Y))
+	}
+	if e := c.level.ElevatorAt(c.Position); e != nil && e.HasLanding(c.Position.Y) {
+		for _, landing := range e.Landings {
+			if landing != c.Position.Y {
+				add(NewVec3(c.Position.X, landing, c.Position.Z))
+			}
+		}
+	}
+	if c.Position.Y > 0 {
+		if below := cellAt(c.Position.Subtract(Y)); below != nil && below.Ground.Type == GroundLadder {
+			neighbors = append(neighbors, below)
+		}
+	}
+
 	return neighbors
 
 }
 
+func (c *Cell) FaceTowards(other *Cell) *Face {
+	if FACE, ok := c.FaceIndexTowards(other); ok {
+		return &c.Faces[FACE]
+	}
+	return &Face{}
+}
+
+func (c *Cell) FaceIndexTowards(other *Cell) (FaceIndex, bool) {
+	delta := other.Position.Subtract(c.Position)
+
+	for FACE := range FACES {
+		direction := FACE_DIRECTION[FACE]
+		if delta.X == direction.X && delta.Z == direction.Z {
+			return FACE, true
+		}
+	}
+
+	return 0, false
+}
+
+// DoorBetween finds the door separating two horizontally neighbouring cells. The door can
+// belong to either cell.
+func DoorBetween(a *Cell, b *Cell) (FaceRef, bool) {
+	if a.Position.Y != b.Position.Y {
+		return FaceRef{}, false
+	}
+	for _, pair := range [][2]*Cell{{a, b}, {b, a}} {
+		FACE, ok := pair[0].FaceIndexTowards(pair[1])
+		if ok && pair[0].Faces[FACE].Type == FaceDoor {
+			return FaceRef{pair[0], FACE}, true
+		}
+	}
+	return FaceRef{}, false
+}
+
+func (c *Cell) Center2D() Vec2 {
+	return c.Position.AddXYZ(0.5, 0, 0.5).To2D()
+}
+
 func (c *Cell) PathNeighborCost(to astar.Pather) float64 {
 	other := to.(*Cell)
-	return c.Position.Distance(other.Position)
+	cost := c.Position.Distance(other.Position)
+
+	if c.Position.Y != other.Position.Y && c.level.ElevatorAt(c.Position) != nil && c.level.ElevatorAt(other.Position) != nil {
+		cost += ELEVATOR_WAIT_COST
+	}
+	return cost
+}
+
+func (c *Cell) IsElevatorLanding() bool {
+	e := c.level.ElevatorAt(c.Position)
+	return e != nil && e.HasLanding(c.Position.Y)
 }
 
 func (c *Cell) PathEstimatedCost(to astar.Pather) float64 {
@@ -253,6 +429,10 @@ func (cell *Cell) Draw(g *Game) {
 	cellPos := cell.Position
 	cell.Ground.Draw(g, cellPos)
 
+	if cell.Ground.Checkpoint && g.EditorEnabled {
+		rl.DrawCylinderWires(cellPos.AddXYZ(0.5, 0, 0.5).Raylib(), 0.3, 0.3, 0.05, 12, rl.Green)
+	}
+
 	if cell.Ground.Type == GroundStair {
 		offset := FACE_DIRECTION[cell.Ground.StairDirection].Add(Y)
 		forwardUp := cell.level.GetCell(cell.Position.Add(offset))
@@ -269,6 +449,8 @@ func (cell *Cell) Draw(g *Game) {
 		switch face.Type {
 		case FaceWall:
 			rl.DrawModelEx(g.GetModel("wall"), center.Raylib(), Y.Negate().Raylib(), float32(FACE_DEGREE[FACE]), XYZ.Raylib(), rl.White)
+		case FaceWindow:
+			face.DrawWindow(g, center, Y.Negate(), float32(FACE_DEGREE[FACE]))
 		case FaceDoor:
 			if face.body != nil {
 				pos := face.body.Position()
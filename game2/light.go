@@ -0,0 +1,128 @@
+package game2
+
+import (
+	"cmp"
+	"image/color"
+	"math"
+	"slices"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const LIGHT_FIXTURE_RADIUS = 0.06
+
+// Entities get the lights near them picked within ENTITY_LIGHT_REACH, about as far as a monster
+// reaches with its arms.
+const ENTITY_LIGHT_REACH = 2.5
+
+// PlacedLight is a point or spot light saved with the level. Cone angles are in degrees, and
+// the light fades out completely at Radius.
+type PlacedLight struct {
+	Type        LightType
+	Position    Vec3
+	Target      Vec3
+	Color       color.RGBA
+	Strength    float64
+	Radius      float64
+	CutOff      float64
+	OuterCutOff float64
+
+	mask int
+}
+
+// On tells whether the light shines. Lights in a cell with a light node follow the power
+// network, the rest are always on.
+func (light *PlacedLight) On(g *Game) bool {
+	node := g.Level.Power.Consumer(PowerLight, light.Position.Floor(), 0)
+	return node == nil || node.Powered()
+}
+
+func (light *PlacedLight) Apply(m *MainShader) {
+	switch light.Type {
+	case LIGHT_SPOT:
+		m.LightSpot(light.Position, light.Target, light.CutOff, light.OuterCutOff, light.Color, light.Strength)
+	default:
+		m.LightPoint(light.Position, light.Color, light.Strength)
+	}
+	m.LightFalloff(light.Radius)
+	m.LightMaskTile(light.mask)
+}
+
+// DistanceToBox is how far the light is from the nearest point of the box.
+func (light *PlacedLight) DistanceToBox(aa Vec3, bb Vec3) float64 {
+	nearest := NewVec3(
+		Clamp(light.Position.X, aa.X, bb.X),
+		Clamp(light.Position.Y, aa.Y, bb.Y),
+		Clamp(light.Position.Z, aa.Z, bb.Z),
+	)
+	return nearest.Distance(light.Position)
+}
+
+// SelectLights fills the shader slots left after the frame's own lights with the placed
+// lights nearest to the box, skipping any that can't reach it or got no tile of the light mask
+// to keep them behind walls. Call it before each batch of draws that should be lit by them.
+func (g *Game) SelectLights(aa Vec3, bb Vec3) {
+	type candidate struct {
+		light    *PlacedLight
+		distance float64
+	}
+
+	candidates := make([]candidate, 0, len(g.Level.Lights))
+	for i := range g.Level.Lights {
+		light := &g.Level.Lights[i]
+
+		distance := light.DistanceToBox(aa, bb)
+		if distance < light.Radius && light.mask >= 0 && light.On(g) {
+			candidates = append(candidates, candidate{light, distance})
+		}
+	}
+
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return cmp.Compare(a.distance, b.distance)
+	})
+
+	for _, c := range candidates[:min(len(candidates), g.MainShader.LightsFree())] {
+		c.light.Apply(g.MainShader)
+	}
+	g.MainShader.UpdateValues()
+}
+
+// SelectLightsAround selects the lights for something drawn on the floor of pos that reaches
+// out to the given distance from it.
+func (g *Game) SelectLightsAround(pos Vec3, reach float64) {
+	y := math.Floor(pos.Y)
+	g.SelectLights(NewVec3(pos.X-reach, y, pos.Z-reach), NewVec3(pos.X+reach, y+1, pos.Z+reach))
+}
+
+func (l *Level) LightAt(pos Vec3) int {
+	cellPos := pos.Floor()
+	return slices.IndexFunc(l.Lights, func(light PlacedLight) bool {
+		return light.Position.Floor() == cellPos
+	})
+}
+
+func (l *Level) DrawLights(g *Game, maxY int) {
+	for i := range l.Lights {
+		light := &l.Lights[i]
+		if light.Position.Y > float64(maxY+1) {
+			continue
+		}
+
+		g.SelectLightsAround(light.Position, LIGHT_FIXTURE_RADIUS)
+
+		col := rl.DarkGray
+		if light.On(g) {
+			col = light.Color
+		}
+		rl.DrawSphere(light.Position.Raylib(), LIGHT_FIXTURE_RADIUS, col)
+	}
+}
+
+func (l *Level) DrawLightsOverlay(g *Game) {
+	for _, light := range l.Lights {
+		rl.DrawSphereWires(light.Position.Raylib(), float32(light.Radius), 6, 12, color.RGBA{light.Color.R, light.Color.G, light.Color.B, 60})
+		if light.Type == LIGHT_SPOT {
+			rl.DrawLine3D(light.Position.Raylib(), light.Target.Raylib(), light.Color)
+		}
+	}
+}
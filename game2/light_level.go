@@ -0,0 +1,157 @@
+package game2
+
+import (
+	"image/color"
+	"math"
+	"slices"
+)
+
+var AMBIENT = color.RGBA{50, 50, 50, 255}
+
+// Below LIGHT_LEVEL_DARK the player counts as hidden in darkness.
+const LIGHT_LEVEL_DARK = 0.3
+
+// Cached cell light levels are dropped once the sun has moved LIGHT_CACHE_SUN_DRIFT away from
+// where it was when they were taken.
+const LIGHT_CACHE_SUN_DRIFT = 0.05
+
+func Luminance(c color.RGBA) float64 {
+	return (0.2126*float64(c.R) + 0.7152*float64(c.G) + 0.0722*float64(c.B)) / 255
+}
+
+// LightLevel estimates how brightly an upward facing surface at pos is lit, on the same scale
+// as the lighting shader: ambient, the sun wherever the shader doesn't shadow it, placed
+// lights that can see pos and the player's flashlight. It only reads level data and the
+// physics space, so it runs without rendering anything.
+func (g *Game) LightLevel(pos Vec3) float64 {
+	level := g.SteadyLightLevel(pos)
+
+	if beam, ok := g.Player.Flashlight(); ok && beam.Illuminates(pos) && g.LineOfSight(beam.Position, pos) {
+		level += beam.Strength * SpotCone(beam.Position, beam.Target, pos, beam.Inner, beam.Outer)
+	}
+
+	return level
+}
+
+// SteadyLightLevel is LightLevel without the player's flashlight, which moves every frame.
+func (g *Game) SteadyLightLevel(pos Vec3) float64 {
+	level := Luminance(AMBIENT)
+
+	direction, sunColor, sunStrength := g.SunLight()
+	if g.SunReaches(pos, direction) {
+		level += Luminance(sunColor) * sunStrength * -direction.Y
+	}
+
+	for i := range g.Level.Lights {
+		light := &g.Level.Lights[i]
+		if light.On(g) {
+			level += light.LevelAt(g, pos)
+		}
+	}
+
+	return level
+}
+
+// lightCache keeps the steady light level of cells across frames for path costs, until a
+// light is placed, removed or switched or the sun has moved on.
+type lightCache struct {
+	levels map[*Cell]float64
+	on     []bool
+	sun    Vec3
+}
+
+func (g *Game) UpdateLightCache() {
+	on := make([]bool, len(g.Level.Lights))
+	for i := range g.Level.Lights {
+		on[i] = g.Level.Lights[i].On(g)
+	}
+	sun, _, _ := g.SunLight()
+
+	cache := &g.lightCache
+	if cache.levels == nil || !slices.Equal(on, cache.on) || sun.Distance(cache.sun) > LIGHT_CACHE_SUN_DRIFT {
+		*cache = lightCache{levels: map[*Cell]float64{}, on: on, sun: sun}
+	}
+}
+
+// CellLightLevel is the steady light level in the middle of the cell, from the cache where it
+// can be.
+func (g *Game) CellLightLevel(cell *Cell) float64 {
+	level, ok := g.lightCache.levels[cell]
+	if !ok {
+		level = g.SteadyLightLevel(cell.Position.AddXYZ(0.5, 0, 0.5))
+		if g.lightCache.levels != nil {
+			g.lightCache.levels[cell] = level
+		}
+	}
+	return level
+}
+
+// SunReaches tells whether pos is out in the sun. Where the shader shadows the sun, that is
+// when no drawn floor roofs it over and no wall of its own floor stands between it and the
+// sun below the top of the walls. On stations, with the sun low and outside the shadow map
+// the shader casts no sun shadows, so the sun reaches everywhere there.
+func (g *Game) SunReaches(pos Vec3, direction Vec3) bool {
+	rise := -direction.Y
+	if rise <= 0 {
+		return false
+	}
+	if g.IsStation || rise < SUN_SHADOW_MIN_ELEVATION || !g.SunShadowCovers(pos, direction) {
+		return true
+	}
+
+	for y := math.Floor(pos.Y) + 1; y <= float64(min(g.DrawMaxY(), CHUNK_HEIGHT-1)); y++ {
+		cell := g.Level.PeekCell(NewVec3(pos.X, y, pos.Z))
+		if cell != nil && (cell.Ground.Type == GroundFloor || cell.Ground.Type == GroundStair) {
+			return false
+		}
+	}
+
+	t := (math.Floor(pos.Y) + 1 - pos.Y) / rise
+	return g.LineOfSight(pos, pos.Subtract(direction.Scale(t)))
+}
+
+// LevelAt follows the shader: the light only reaches its own floor, fades out at Radius and
+// stops at walls.
+func (light *PlacedLight) LevelAt(g *Game, pos Vec3) float64 {
+	if math.Floor(pos.Y) != math.Floor(light.Position.Y) {
+		return 0
+	}
+
+	distance := light.Position.Distance(pos)
+	if distance >= light.Radius || !g.LineOfSight(light.Position, pos) {
+		return 0
+	}
+
+	fade := 1 - distance/light.Radius
+	facing := 1.0
+	if distance > 0 {
+		facing = max((light.Position.Y-pos.Y)/distance, 0)
+	}
+
+	level := Luminance(light.Color) * light.Strength * fade * fade * facing
+	if light.Type == LIGHT_SPOT {
+		level *= SpotCone(light.Position, light.Target, pos, light.CutOff, light.OuterCutOff)
+	}
+	return level
+}
+
+// SpotCone is how much of a spot light pointing from position at target falls on pos, fading
+// from full inside cutOff to nothing at outerCutOff degrees. A light pointing nowhere lights
+// nothing.
+func SpotCone(position Vec3, target Vec3, pos Vec3, cutOff float64, outerCutOff float64) float64 {
+	direction := target.Subtract(position)
+	if direction.Length() == 0 {
+		return 0
+	}
+
+	toPos := pos.Subtract(position)
+	if toPos.Length() == 0 {
+		return 1
+	}
+
+	theta := toPos.Normalize().DotProduct(direction.Normalize())
+	inner := math.Cos(cutOff * math.Pi / 180)
+	outer := math.Cos(outerCutOff * math.Pi / 180)
+
+	return Clamp((theta-outer)/(inner-outer), 0, 1)
+}
@@ -0,0 +1,143 @@
+package game2
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/jakecoffman/cp"
+)
+
+// newLightTestGame builds just enough of a game for the light queries: a physics space, an
+// empty level and a player without a flashlight, under the station's light from straight above.
+func newLightTestGame() *Game {
+	g := &Game{
+		Space:     cp.NewSpace(),
+		Level:     (&Level{}).Init(),
+		IsStation: true,
+	}
+	g.Player = &Player{body: g.Space.AddBody(cp.NewBody(1, cp.INFINITY))}
+	return g
+}
+
+func TestLightStopsAtWalls(t *testing.T) {
+	g := newLightTestGame()
+
+	wall := g.Level.GetCell(NewVec3(3, 0, 2))
+	wall.Faces[FACE_WEST].Type = FaceWall
+	wall.Wake(g)
+
+	g.Level.Lights = []PlacedLight{{
+		Type:     LIGHT_POINT,
+		Position: NewVec3(2.5, 0.9, 2.5),
+		Color:    rl.White,
+		Strength: 1,
+		Radius:   4,
+	}}
+	light := &g.Level.Lights[0]
+
+	lit := NewVec3(3.5, 0, 2.5)
+	behind := NewVec3(4.5, 0, 2.5)
+	open := NewVec3(2.5, 0, 4.5)
+
+	if level := light.LevelAt(g, lit); level <= 0 {
+		t.Errorf("light reaches %v with %v, want some", lit, level)
+	}
+	if level := light.LevelAt(g, behind); level != 0 {
+		t.Errorf("light reaches %v behind the wall with %v, want none", behind, level)
+	}
+	if light.LevelAt(g, open) <= 0 {
+		t.Errorf("light doesn't reach %v in the open", open)
+	}
+
+	if g.LightLevel(lit) <= g.LightLevel(behind) {
+		t.Errorf("lit cell is no brighter than the cell behind the wall")
+	}
+}
+
+func TestSunNeedsOpenSky(t *testing.T) {
+	g := newLightTestGame()
+	g.IsStation = false
+	g.Day = 0.5
+	direction, _, _ := g.SunLight()
+
+	g.Level.GetCell(NewVec3(1, 0, 1)).Ground.Type = GroundFloor
+	g.Level.GetCell(NewVec3(1, 1, 1)).Ground.Type = GroundFloor
+	g.Level.GetCell(NewVec3(2, 0, 1)).Ground.Type = GroundFloor
+
+	roofed := NewVec3(1.5, 0.1, 1.5)
+	open := NewVec3(2.5, 0.1, 1.5)
+
+	if g.SunReaches(roofed, direction) {
+		t.Errorf("sun reaches %v under a floor", roofed)
+	}
+	if !g.SunReaches(open, direction) {
+		t.Errorf("sun doesn't reach %v under the open sky", open)
+	}
+	if g.LightLevel(roofed) >= g.LightLevel(open) {
+		t.Errorf("roofed cell is as bright as the open one")
+	}
+
+	g.Level.GetCell(NewVec3(30, 1, 30)).Ground.Type = GroundFloor
+	if far := NewVec3(30.5, 0.1, 30.5); !g.SunReaches(far, direction) {
+		t.Errorf("sun doesn't reach %v under a floor outside the shadow map", far)
+	}
+
+	g.IsStation = true
+	if !g.SunReaches(roofed, direction) {
+		t.Errorf("station light doesn't reach %v under a floor", roofed)
+	}
+}
+
+func TestSlantedSunStopsAtWalls(t *testing.T) {
+	g := newLightTestGame()
+	g.IsStation = false
+	direction := NewVec3(1, -1, 0).Normalize()
+
+	wall := g.Level.GetCell(NewVec3(3, 0, 5))
+	wall.Faces[FACE_WEST].Type = FaceWall
+	wall.Wake(g)
+
+	shaded := NewVec3(4.2, 0.1, 5.5)
+	open := NewVec3(5.5, 0.1, 5.5)
+
+	if g.SunReaches(shaded, direction) {
+		t.Errorf("slanted sun reaches %v behind the wall", shaded)
+	}
+	if !g.SunReaches(open, direction) {
+		t.Errorf("slanted sun doesn't reach %v clear of the wall", open)
+	}
+}
+
+func TestSpotCone(t *testing.T) {
+	position := NewVec3(0, 2, 0)
+	target := NewVec3(0, 0, 0)
+
+	if cone := SpotCone(position, target, NewVec3(0.2, 0, 0), 30, 45); cone != 1 {
+		t.Errorf("point inside the cone gets %v, want 1", cone)
+	}
+	if cone := SpotCone(position, target, NewVec3(3, 0, 0), 30, 45); cone != 0 {
+		t.Errorf("point outside the cone gets %v, want 0", cone)
+	}
+	if cone := SpotCone(position, position, target, 30, 45); cone != 0 {
+		t.Errorf("spot without a direction gets %v, want 0", cone)
+	}
+
+	g := newLightTestGame()
+	light := PlacedLight{
+		Type:        LIGHT_SPOT,
+		Position:    NewVec3(2.5, 0.9, 2.5),
+		Target:      NewVec3(2.5, -0.1, 2.5),
+		Color:       rl.White,
+		Strength:    1,
+		Radius:      4,
+		CutOff:      20,
+		OuterCutOff: 30,
+	}
+
+	if light.LevelAt(g, NewVec3(2.6, 0, 2.5)) <= 0 {
+		t.Errorf("spot light doesn't reach right below itself")
+	}
+	if level := light.LevelAt(g, NewVec3(4.5, 0, 2.5)); level != 0 {
+		t.Errorf("spot light reaches outside its cone with %v", level)
+	}
+}
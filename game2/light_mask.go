@@ -0,0 +1,94 @@
+package game2
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/jakecoffman/cp"
+)
+
+// Every placed light near the player gets a tile of the mask atlas holding what it can see of
+// its floor, as seen from above over a square twice its radius wide. LIGHT_MASK_GRID must
+// match the define in lighting.fs.
+const LIGHT_MASK_GRID = 8
+const LIGHT_MASK_TILE = 128
+const LIGHT_MASK_RAYS = 90
+const LIGHT_MASK_RANGE = 16.0
+
+// Masks reach a little past the walls they stop at so the wall faces themselves are lit.
+const LIGHT_MASK_BLEED = 0.05
+
+// VisibilityPolygon casts rays from the light against the walls and doors of its floor and
+// returns where they stop, in order around the light.
+func (light *PlacedLight) VisibilityPolygon(g *Game) []Vec2 {
+	from := light.Position.To2D()
+	filter := cp.NewShapeFilter(0, Category(light.Position.Y, true, false), Category(light.Position.Y, true, false))
+
+	verts := make([]Vec2, LIGHT_MASK_RAYS)
+	for i := range verts {
+		angle := float64(i) / LIGHT_MASK_RAYS * math.Pi * 2
+		dir := NewVec2(math.Cos(angle), math.Sin(angle))
+		to := from.Add(dir.Scale(light.Radius))
+
+		result := g.Space.SegmentQueryFirst(from.CP(), to.CP(), 0, filter)
+		distance := min(result.Alpha*light.Radius+LIGHT_MASK_BLEED, light.Radius)
+
+		verts[i] = from.Add(dir.Scale(distance))
+	}
+	return verts
+}
+
+// RenderLightMasks draws the visibility polygon of each light near the player into its own
+// tile of LightMaskTexture, nearest lights first. Lights left without a tile get mask -1,
+// which SelectLights leaves out; the shader would light them unmasked, straight through walls.
+func (g *Game) RenderLightMasks() {
+	playerPos := g.Player.Position3D()
+	tile := 0
+
+	reach := func(light *PlacedLight) float64 {
+		return light.Position.To2D().Distance(playerPos.To2D()) - light.Radius
+	}
+
+	lights := make([]*PlacedLight, len(g.Level.Lights))
+	for i := range g.Level.Lights {
+		lights[i] = &g.Level.Lights[i]
+		lights[i].mask = -1
+	}
+	slices.SortFunc(lights, func(a, b *PlacedLight) int {
+		return cmp.Compare(reach(a), reach(b))
+	})
+
+	BeginTextureMode(g.LightMaskTexture, func() {
+		rl.ClearBackground(rl.Black)
+		rl.DisableBackfaceCulling()
+
+		for _, light := range lights {
+			if tile >= LIGHT_MASK_GRID*LIGHT_MASK_GRID || reach(light) > LIGHT_MASK_RANGE {
+				break
+			}
+
+			light.mask = tile
+			origin := NewVec2(float64(tile%LIGHT_MASK_GRID), float64(tile/LIGHT_MASK_GRID))
+			tile++
+
+			toAtlas := func(pos Vec2) rl.Vector2 {
+				local := pos.Subtract(light.Position.To2D()).Scale(1 / (light.Radius * 2)).Add(NewVec2(0.5, 0.5))
+				atlas := origin.Add(local).Scale(LIGHT_MASK_TILE)
+				return rl.Vector2{X: float32(atlas.X), Y: float32(atlas.Y)}
+			}
+
+			center := toAtlas(light.Position.To2D())
+			verts := light.VisibilityPolygon(g)
+			for j, a := range verts {
+				b := verts[(j+1)%len(verts)]
+				rl.DrawTriangle(center, toAtlas(a), toAtlas(b), rl.White)
+			}
+		}
+
+		rl.EnableBackfaceCulling()
+	})
+
+	g.MainShader.LightMasks.Set(g.LightMaskTexture.Texture)
+}
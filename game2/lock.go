@@ -0,0 +1,160 @@
+package game2
+
+import (
+	"encoding/gob"
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/beefsack/go-astar"
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/jakecoffman/cp"
+)
+
+const LOCK_REACH = 1.2
+const LOCK_CLOSED_ANGLE = 0.05
+const KEYCARD_LEVELS = 9
+
+func init() {
+	gob.Register(&Keycard{})
+}
+
+type LockState = uint8
+
+const (
+	LockUnlocked = LockState(iota)
+	LockLocked
+	LockKeycard
+)
+
+type Keycard struct {
+	Level int
+}
+
+func (k *Keycard) Name() string {
+	return fmt.Sprintf("Keycard level %v", k.Level)
+}
+
+func (k *Keycard) Color() color.RGBA {
+	return rl.SkyBlue
+}
+
+func (k *Keycard) UpdateInventory(g *Game, p *Player) {}
+func (k *Keycard) UpdateWorld(g *Game, w *WorldItem)  {}
+func (k *Keycard) DrawHUD(g *Game, line *LineLayout)  {}
+func (k *Keycard) Save(g *Game)                       {}
+
+func (face *Face) Locked() bool {
+	return face.Lock != LockUnlocked
+}
+
+// CanUnlock tells whether someone holding a keycard of the given level may pass. Doors that
+// are simply locked never open.
+func (face *Face) CanUnlock(keycardLevel int) bool {
+	return face.Lock == LockUnlocked || (face.Lock == LockKeycard && keycardLevel >= face.LockLevel)
+}
+
+// SetLocked pins a door closed with a rotary limit, or frees it again.
+func (face *Face) SetLocked(g *Game, FACE FaceIndex, locked bool) {
+	if locked && face.lock == nil {
+		angle := FACE_DEGREE[FACE_NEXT[FACE]] * rl.Deg2rad
+		face.lock = g.Space.AddConstraint(cp.NewRotaryLimitJoint(g.Space.StaticBody, face.body, angle, angle))
+		face.lock.SetMaxForce(1e8)
+	}
+	if !locked && face.lock != nil {
+		g.Space.RemoveConstraint(face.lock)
+		face.lock = nil
+	}
+}
+
+// UpdateLocks releases keycard doors while an authorised player stands by and pins them
+// again once they have swung shut.
+func (g *Game) UpdateLocks() {
+	playerPos := g.Player.Position3D()
+	keycardLevel := g.Player.KeycardLevel()
+
+	for _, ref := range g.Level.doors {
+		face := ref.Face()
+
+		if !face.Locked() {
+			face.SetLocked(g, ref.Index, false)
+			continue
+		}
+
+		pos := Vec2FromCP(face.body.Position())
+		near := math.Floor(playerPos.Y) == ref.Cell.Position.Y && pos.Distance(playerPos.To2D()) <= LOCK_REACH
+
+		if near && face.CanUnlock(keycardLevel) {
+			face.SetLocked(g, ref.Index, false)
+			continue
+		}
+
+		closed := FACE_DEGREE[FACE_NEXT[ref.Index]] * rl.Deg2rad
+		if math.Abs(face.body.Angle()-closed) < LOCK_CLOSED_ANGLE {
+			face.SetLocked(g, ref.Index, true)
+		}
+	}
+}
+
+func (p *Player) KeycardLevel() int {
+	level := 0
+	for _, item := range p.Items {
+		if keycard, ok := item.(*Keycard); ok {
+			level = max(level, keycard.Level)
+		}
+	}
+	return level
+}
+
+// PathRules decide which doors a path may lead through, and how much lit cells are avoided.
+type PathRules struct {
+	KeycardLevel  int
+	IgnoreLocks   bool
+	LightAversion float64
+
+	lightLevel func(cell *Cell) float64
+}
+
+func (r *PathRules) Blocks(from *Cell, to *Cell) bool {
+	ref, ok := DoorBetween(from, to)
+	if !ok || r.IgnoreLocks {
+		return false
+	}
+	return !ref.Face().CanUnlock(r.KeycardLevel)
+}
+
+func (r *PathRules) LightCost(cell *Cell) float64 {
+	if r.LightAversion == 0 || r.lightLevel == nil {
+		return 0
+	}
+
+	return r.LightAversion * max(r.lightLevel(cell)-Luminance(AMBIENT), 0)
+}
+
+// pathNode walks the cell graph under a set of rules.
+type pathNode struct {
+	cell  *Cell
+	rules *PathRules
+}
+
+func (n pathNode) PathNeighbors() []astar.Pather {
+	neighbors := n.cell.PathNeighbors()
+	nodes := make([]astar.Pather, 0, len(neighbors))
+
+	for _, neighbor := range neighbors {
+		next := neighbor.(*Cell)
+		if !n.rules.Blocks(n.cell, next) {
+			nodes = append(nodes, pathNode{next, n.rules})
+		}
+	}
+	return nodes
+}
+
+func (n pathNode) PathNeighborCost(to astar.Pather) float64 {
+	next := to.(pathNode).cell
+	return n.cell.PathNeighborCost(next) + n.rules.LightCost(next)
+}
+
+func (n pathNode) PathEstimatedCost(to astar.Pather) float64 {
+	return n.cell.PathEstimatedCost(to.(pathNode).cell)
+}
@@ -1,67 +1,95 @@
 package game2
 
 import (
+	"encoding/gob"
 	"image/color"
 	"math"
+	"time"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 	"github.com/jakecoffman/cp"
 )
 
+const MONSTER_BODY_DAMAGE = 25
+const MONSTER_TIP_DAMAGE = 15
+
+func init() {
+	gob.Register(&Monster{})
+}
+
 type Monster struct {
+	EntityBase
+
+	Type       string
 	Y          float64
 	YVelocity  float64
 	Radius     float64
 	body       *cp.Body
 	shape      *cp.Shape
 	PathFinder *PathFinder
+	Perception MonsterPerception
 
-	arms []*MonsterArm
+	Body       BodyState
+	Arms       []*MonsterArm
+	definition *MonsterDefinition
 
-	SavePosition Vec2
+	doorStuck time.Duration
+	batterAt  time.Duration
 }
 
 func (p *Monster) Update(g *Game) {
 	monsterPos := p.Position3D()
 
+	p.Perception.Update(g, p)
+
 	p.PathFinder.SetPosition(p.Position3D())
-	p.PathFinder.SetTarget(g.Player.Position3D())
+	if p.Perception.HasTarget() {
+		p.PathFinder.SetTarget(p.Perception.LastKnownPlayerPosition)
+	} else {
+		p.PathFinder.SetIdle(true)
+	}
 
 	force := cp.Vector{}
 
-	if !p.PathFinder.Idle && len(p.PathFinder.Path) >= 2 {
-		a := p.PathFinder.Path[0]
-		b := p.PathFinder.Path[1]
-
-		force3D := b.Lerp(a, 0.5).Subtract(monsterPos)
+	if target, ok := p.PathFinder.SteeringTarget(); ok {
+		force3D := target.Subtract(monsterPos)
 		force.X = force3D.X
 		force.Y = force3D.Z
 	}
 
 	forceMag := force.Length()
 
+	direction := Vec2FromCP(force).Normalize()
+
 	if forceMag != 0 {
-		force = force.Normalize().Mult(p.body.Mass() * 60)
+		force = force.Normalize().Mult(p.body.Mass() * p.definition.BodyForce)
 	}
 
-	p.body.SetForce(force)
-
-	newVelocity := Vec2FromCP(p.body.Velocity()).Scale(math.Pow(0.01, g.TimeDelta.Seconds()*4))
+	newVelocity := Vec2FromCP(p.body.Velocity()).Scale(math.Pow(0.01, g.TimeDelta.Seconds()*p.definition.BodyDamping))
 	p.body.SetVelocity(newVelocity.X, newVelocity.Y)
 
-	p.Y, p.YVelocity = UpdatePhysicsY(g, p.shape, p.Y, p.YVelocity)
+	if p.UpdateClimb(g) {
+		SetFloorFilter(p.shape, p.Y)
+	} else {
+		p.Y, p.YVelocity = UpdatePhysicsY(g, p.shape, p.Y, p.YVelocity)
+	}
+
+	p.UpdateDoors(g)
+	p.UpdateElevator(g)
 
-	for _, arm := range p.arms {
+	for _, arm := range p.Arms {
 
-		tip := arm.segments[len(arm.segments)-1]
+		tip := arm.Segments[len(arm.Segments)-1]
+
+		force = force.Add(p.UpdateGrip(g, arm, direction).CP())
 
 		totalCurlAngle := 0.0
-		curlAngles := make([]float64, len(arm.segments)-2)
+		curlAngles := make([]float64, len(arm.Segments)-2)
 
-		for i, segment := range arm.segments[:len(arm.segments)-2] {
+		for i, segment := range arm.Segments[:len(arm.Segments)-2] {
 			a := segment.body.Position()
-			b := arm.segments[i+1].body.Position()
-			c := arm.segments[i+2].body.Position()
+			b := arm.Segments[i+1].body.Position()
+			c := arm.Segments[i+2].body.Position()
 			v1 := b.Sub(a)
 			v2 := c.Sub(b)
 			cross := v1.Cross(v2)
@@ -72,15 +100,18 @@ func (p *Monster) Update(g *Game) {
 			curlAngles[i] = angle
 		}
 		for i, angle := range curlAngles {
-			segment := arm.segments[i]
-			segment.body.SetTorque(angle * tip.body.Moment() * 500)
+			segment := arm.Segments[i]
+			segment.body.SetTorque(angle * tip.body.Moment() * p.definition.CurlTorque)
 		}
 
-		if !p.PathFinder.Idle && len(p.PathFinder.Path) >= 2 && p.PathFinder.PathLength > 3 {
+		if !p.PathFinder.Idle && len(p.PathFinder.Path) >= 2 && p.PathFinder.PathLength > p.definition.MinChaseLength {
 			closestI := 0
 			closestDistance := math.Inf(1)
 
 			for i, point := range p.PathFinder.Path[:len(p.PathFinder.Path)-1] {
+				if math.Abs(point.Y-p.Y) > 1 {
+					continue
+				}
 				distance := point.Distance(tip.Position3D())
 				if distance < closestDistance {
 					closestDistance = distance
@@ -88,39 +119,53 @@ func (p *Monster) Update(g *Game) {
 				}
 			}
 
-			if closestDistance > 3 {
-				arm.tipTarget = p.PathFinder.Path[closestI]
+			if closestDistance > p.definition.TipReach {
+				arm.TipTarget = p.PathFinder.Path[closestI]
 			} else {
-				arm.tipTarget = p.PathFinder.Path[closestI].Lerp(p.PathFinder.Path[closestI+1], 1)
+				arm.TipTarget = p.PathFinder.Path[closestI].Lerp(p.PathFinder.Path[closestI+1], 1)
 			}
 
+		} else if p.Perception.HasTarget() {
+			arm.TipTarget = p.Perception.LastKnownPlayerPosition
 		} else {
-			arm.tipTarget = g.Player.Position3D()
+			arm.TipTarget = monsterPos
 		}
 
-		delta := arm.tipTarget.Subtract(tip.Position3D())
-		currentDir := cp.ForAngle(tip.body.Angle())
-		relativeAngle := math.Atan2(currentDir.Cross(delta.Chipmunk()), currentDir.Dot(delta.Chipmunk()))
+		if arm.grip == nil {
+			delta := arm.TipTarget.Subtract(tip.Position3D())
+			currentDir := cp.ForAngle(tip.body.Angle())
+			relativeAngle := math.Atan2(currentDir.Cross(delta.Chipmunk()), currentDir.Dot(delta.Chipmunk()))
 
-		tip.body.SetTorque(relativeAngle * tip.body.Moment() * 70)
-		tip.body.SetForce(delta.Normalize().Scale(50 * tip.body.Mass()).Chipmunk())
+			tip.body.SetTorque(relativeAngle * tip.body.Moment() * p.definition.TipTorque)
+			tip.body.SetForce(delta.Normalize().Scale(p.definition.TipForce * tip.body.Mass()).Chipmunk())
+		}
 
-		for _, segment := range arm.segments {
+		// Segments follow their own ground, but collide as part of the body's floor so an arm
+		// reaching up or down a stair doesn't snag on the other floor's walls.
+		for _, segment := range arm.Segments {
 			segment.Y, segment.YVelocity = UpdatePhysicsY(g, segment.shape, segment.Y, segment.YVelocity)
+			SetFloorFilter(segment.shape, p.Y)
 		}
 	}
+
+	p.body.SetForce(force)
 }
 
 type MonsterArm struct {
-	segments  []*MonsterArmSegment
-	tipTarget Vec3
+	Segments  []*MonsterArmSegment
+	TipTarget Vec3
+	GaitAngle float64
+
+	Gripping  bool
+	GripPoint Vec2
+	grip      *cp.Constraint
 }
 
 func (ma *MonsterArm) Tip() *MonsterArmSegment {
-	return ma.segments[len(ma.segments)-1]
+	return ma.Segments[len(ma.Segments)-1]
 }
 func (ma *MonsterArm) Base() *MonsterArmSegment {
-	return ma.segments[0]
+	return ma.Segments[0]
 }
 
 type MonsterArmSegment struct {
@@ -130,8 +175,19 @@ type MonsterArmSegment struct {
 	Length float64
 	Width  float64
 
+	Body      BodyState
 	Y         float64
 	YVelocity float64
+
+	damage float64
+}
+
+func (p *Monster) ContactDamage() float64 {
+	return p.definition.BodyDamage
+}
+
+func (p *MonsterArmSegment) ContactDamage() float64 {
+	return p.damage
 }
 
 func (p *Monster) Position3D() Vec3 {
@@ -141,58 +197,65 @@ func (p *MonsterArmSegment) Position3D() Vec3 {
 	return Vec3From2D(Vec2FromCP(p.body.Position()), p.Y)
 }
 
-func (p *Monster) ToSave(g *Game) *Monster {
-	if p == nil {
-		return nil
+func (p *Monster) Save(g *Game) {
+	p.Body = NewBodyState(p.body)
+
+	for _, arm := range p.Arms {
+		for _, segment := range arm.Segments {
+			segment.Body = NewBodyState(segment.body)
+		}
 	}
-	p.SavePosition = Vec2FromCP(p.body.Position())
-	return p
 }
 
-func (p *Monster) Load(g *Game) *Monster {
-	p.Radius = 0.3
+func (p *Monster) Spawn(g *Game) {
+	p.definition = g.GetMonsterDefinition(p.Type)
+	p.Radius = p.definition.BodyRadius
 	if p.PathFinder == nil {
 		p.PathFinder = NewPathFinder(g.Level)
 	}
 	p.PathFinder.level = g.Level
+	p.PathFinder.Rules.IgnoreLocks = !p.definition.AvoidLockedDoors
+	p.PathFinder.Rules.LightAversion = p.definition.LightAversion
+	p.PathFinder.Rules.lightLevel = g.CellLightLevel
 
-	mass := p.Radius * p.Radius
+	mass := p.Radius * p.Radius * p.definition.BodyDensity
 	body := g.Space.AddBody(cp.NewBody(mass, cp.MomentForCircle(mass, 0, p.Radius, Vec2{2, 2}.CP())))
-	position := p.SavePosition
+	position := p.Body.Position
 	body.SetPosition(position.CP())
 
 	p.shape = g.Space.AddShape(cp.NewCircle(body, p.Radius, Vec2{}.CP()))
 	p.shape.SetElasticity(0)
 	p.shape.SetFriction(0)
 	p.body = body
-	g.Monster = p
 	p.shape.Filter.Group = GroupMonster
+	p.shape.SetCollisionType(CollisionMonster)
+	p.shape.UserData = p
 
-	p.arms = make([]*MonsterArm, 0)
+	savedArms := p.Arms
+	p.Arms = make([]*MonsterArm, 0)
 
-	for range 5 {
+	for i := range p.definition.Arms {
 
 		arm := &MonsterArm{
-			// Index:     i,
-			// GaitAngle: float64(i) / float64(ARMS),
-			// Monster:   monster,
-			segments: make([]*MonsterArmSegment, 0),
+			GaitAngle: float64(i) / float64(p.definition.Arms),
+			Segments:  make([]*MonsterArmSegment, 0),
 		}
-		p.arms = append(p.arms, arm)
+		p.Arms = append(p.Arms, arm)
 
 		prevBody := p.body
 		prevPosition := position
 
-		for i := range 12 {
+		for _, definition := range p.definition.Segments {
 
 			segment := &MonsterArmSegment{
-				Length: p.Radius * 0.6,
-				Width:  (p.Radius * 1.5) / (1 + float64(i)/5),
+				Length: definition.Length,
+				Width:  definition.Width,
 				Y:      p.Y,
+				damage: definition.Damage,
 			}
-			arm.segments = append(arm.segments, segment)
+			arm.Segments = append(arm.Segments, segment)
 
-			mass := segment.Length * segment.Width * 1.5
+			mass := segment.Length * segment.Width * definition.Density
 
 			segment.body = g.Space.AddBody(cp.NewBody(mass, cp.MomentForBox(mass, segment.Length, segment.Width)))
 			position := prevPosition.AddXY(segment.Length, 0)
@@ -200,18 +263,19 @@ func (p *Monster) Load(g *Game) *Monster {
 
 			segment.shape = g.Space.AddShape(cp.NewBox(segment.body, segment.Length, segment.Width, 0))
 			segment.shape.SetElasticity(0.)
-			segment.shape.SetFriction(0.1)
+			segment.shape.SetFriction(definition.Friction)
 			segment.shape.Filter.Group = GroupMonster
+			segment.shape.SetCollisionType(CollisionMonster)
+			segment.shape.UserData = segment
 
 			constraint := g.Space.AddConstraint(cp.NewPivotJoint(prevBody, segment.body, prevPosition.CP()))
 			constraint.SetMaxForce(1e12)
 
-			if i != 0 {
-				rotaryLimitAngle := rl.Pi / 3
-				rotaryLimit := g.Space.AddConstraint(cp.NewRotaryLimitJoint(prevBody, segment.body, -rotaryLimitAngle, rotaryLimitAngle))
+			if definition.PivotLimit != 0 {
+				rotaryLimit := g.Space.AddConstraint(cp.NewRotaryLimitJoint(prevBody, segment.body, -definition.PivotLimit, definition.PivotLimit))
 				rotaryLimit.SetMaxForce(1e12)
-				stiffness := 5.0 * segment.body.Moment()
-				damping := 2 * math.Sqrt(stiffness*segment.body.Moment())
+				stiffness := definition.SpringStiffness * segment.body.Moment()
+				damping := definition.SpringDamping * math.Sqrt(stiffness*segment.body.Moment())
 				g.Space.AddConstraint(cp.NewDampedRotarySpring(prevBody, segment.body, 0, stiffness, damping))
 			}
 
@@ -219,7 +283,7 @@ func (p *Monster) Load(g *Game) *Monster {
 			prevBody = segment.body
 		}
 
-		for i, segment := range arm.segments {
+		for i, segment := range arm.Segments {
 			f := float64(i)
 			angle := f / 2
 			pos := position.Add(NewVec2(math.Cos(f+math.Pi/2), math.Sin(f+math.Pi/2)).Scale(0.25))
@@ -228,7 +292,60 @@ func (p *Monster) Load(g *Game) *Monster {
 		}
 	}
 
-	return p
+	p.Body.Apply(p.body)
+
+	if p.matchesArms(savedArms) {
+		for i, arm := range p.Arms {
+			saved := savedArms[i]
+			arm.TipTarget = saved.TipTarget
+
+			for j, segment := range arm.Segments {
+				segment.Y = saved.Segments[j].Y
+				segment.YVelocity = saved.Segments[j].YVelocity
+				segment.Body = saved.Segments[j].Body
+				segment.Body.Apply(segment.body)
+			}
+
+			if saved.Gripping {
+				p.Grip(g, arm, saved.GripPoint)
+			}
+		}
+	}
+}
+
+// The joints are always built in the spawn pose, so a saved pose is only restored onto
+// an anatomy with the same shape.
+func (p *Monster) matchesArms(arms []*MonsterArm) bool {
+	if len(arms) != len(p.Arms) {
+		return false
+	}
+	for i, arm := range arms {
+		if len(arm.Segments) != len(p.Arms[i].Segments) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Monster) Despawn(g *Game) {
+	for _, arm := range p.Arms {
+		for _, segment := range arm.Segments {
+			RemoveBody(g.Space, segment.body)
+		}
+	}
+	RemoveBody(g.Space, p.body)
+}
+
+func (p *Monster) DrawOverlay(g *Game) {
+	p.PathFinder.Draw3D(g)
+	p.Perception.Draw3D(g, p)
+	p.DrawGrips(g)
+
+	for _, arm := range p.Arms {
+		tip := arm.Tip()
+
+		rl.DrawLine3D(tip.Position3D().Raylib(), arm.TipTarget.Raylib(), rl.Blue)
+	}
 }
 
 func (p *Monster) Draw3D(g *Game, maxY int) {
@@ -245,9 +362,9 @@ func (p *Monster) Draw3D(g *Game, maxY int) {
 		rl.DrawModelEx(g.GetModel("monster_body"), p.Position3D().Add(Y.Scale(p.Radius)).Raylib(), Y.Negate().Raylib(), float32(p.body.Angle()*rl.Rad2deg), XYZ.Scale(p.Radius).Raylib(), col)
 	}
 
-	for _, arm := range p.arms {
+	for _, arm := range p.Arms {
 
-		for _, segment := range arm.segments {
+		for _, segment := range arm.Segments {
 
 			if math.Floor(segment.Y) <= float64(maxY) {
 
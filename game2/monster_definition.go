@@ -0,0 +1,153 @@
+package game2
+
+import (
+	"encoding/json"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+const MONSTER_DEFINITIONS_PATH = "./monsters/"
+const MONSTER_DEFAULT_TYPE = "default"
+
+type MonsterSegmentDefinition struct {
+	Length   float64
+	Width    float64
+	Density  float64
+	Friction float64
+	Damage   float64
+
+	// Joint to the previous segment. A zero PivotLimit leaves the joint free and unsprung.
+	PivotLimit      float64
+	SpringStiffness float64
+	SpringDamping   float64
+}
+
+type MonsterDefinition struct {
+	BodyRadius  float64
+	BodyDensity float64
+	BodyDamage  float64
+
+	Arms     int
+	Segments []MonsterSegmentDefinition
+
+	BodyForce       float64
+	BodyDamping     float64
+	CurlTorque      float64
+	TipTorque       float64
+	TipForce        float64
+	TipReach        float64
+	MinChaseLength  float64
+	ViewDistance    float64
+	ViewConeRadians float64
+
+	// Each arm holds on for GripFraction of every GaitPeriod seconds.
+	GaitPeriod   float64
+	GripFraction float64
+	GripReach    float64
+	GripMaxForce float64
+	GripPull     float64
+
+	// Force used to push doors on the path open. Doors that will not move are battered
+	// every BatterInterval until they break, if BatterDoors is set.
+	DoorForce      float64
+	BatterDoors    bool
+	BatterInterval float64
+	BatterDamage   float64
+	BatterImpulse  float64
+
+	// Monsters that avoid locked doors path around them instead of battering through.
+	AvoidLockedDoors bool
+
+	// Past DarkViewDistance the player can't be made out while they stand in the dark. Paths
+	// cost LightAversion more per cell for each unit of light above ambient.
+	DarkViewDistance float64
+	LightAversion    float64
+}
+
+func DefaultMonsterDefinition() MonsterDefinition {
+	radius := 0.3
+
+	segments := make([]MonsterSegmentDefinition, 12)
+	for i := range segments {
+		segments[i] = MonsterSegmentDefinition{
+			Length:   radius * 0.6,
+			Width:    (radius * 1.5) / (1 + float64(i)/5),
+			Density:  1.5,
+			Friction: 0.1,
+		}
+		if i != 0 {
+			segments[i].PivotLimit = math.Pi / 3
+			segments[i].SpringStiffness = 5
+			segments[i].SpringDamping = 2
+		}
+	}
+	segments[len(segments)-1].Damage = MONSTER_TIP_DAMAGE
+
+	return MonsterDefinition{
+		BodyRadius:  radius,
+		BodyDensity: 1,
+		BodyDamage:  MONSTER_BODY_DAMAGE,
+
+		Arms:     5,
+		Segments: segments,
+
+		BodyForce:       60,
+		BodyDamping:     4,
+		CurlTorque:      500,
+		TipTorque:       70,
+		TipForce:        50,
+		TipReach:        3,
+		MinChaseLength:  3,
+		ViewDistance:    MONSTER_VIEW_DISTANCE,
+		ViewConeRadians: MONSTER_VIEW_CONE_RADIANS,
+
+		GaitPeriod:   1.2,
+		GripFraction: 0.6,
+		GripReach:    0.3,
+		GripMaxForce: 4,
+		GripPull:     30,
+
+		DoorForce:      20,
+		BatterDoors:    true,
+		BatterInterval: 1,
+		BatterDamage:   20,
+		BatterImpulse:  3,
+
+		DarkViewDistance: 2,
+		LightAversion:    2,
+	}
+}
+
+func LoadMonsterDefinition(path string, definition *MonsterDefinition) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, definition)
+}
+
+func (g *Game) GetMonsterDefinition(name string) *MonsterDefinition {
+	if name == "" {
+		name = MONSTER_DEFAULT_TYPE
+	}
+
+	if definition, ok := g.MonsterDefinitions[name]; ok {
+		return definition
+	}
+
+	definition := DefaultMonsterDefinition()
+	path := filepath.Join(MONSTER_DEFINITIONS_PATH, name+".json")
+
+	if err := LoadMonsterDefinition(path, &definition); err != nil {
+		log.Printf("WARNING! could not load monster \"%v\": %v", name, err)
+	}
+	if len(definition.Segments) < 3 {
+		log.Printf("WARNING! monster \"%v\" needs at least 3 arm segments", name)
+		definition.Segments = DefaultMonsterDefinition().Segments
+	}
+
+	g.MonsterDefinitions[name] = &definition
+	return &definition
+}
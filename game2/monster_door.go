@@ -0,0 +1,103 @@
+package game2
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+const DOOR_HEALTH = 100.0
+const DOOR_STUCK_SPEED = 0.2
+const DOOR_STUCK_DELAY = time.Second
+const BATTER_LOUDNESS = 12.0
+const SHAKE_DECAY = 4.0
+
+// UpdateDoors pushes open the door on the next step of the path, and batters it when it
+// will not give way.
+func (p *Monster) UpdateDoors(g *Game) {
+	path := p.PathFinder.Path
+
+	if p.PathFinder.Idle || len(path) < 2 {
+		p.doorStuck = 0
+		return
+	}
+
+	from := g.Level.GetCell(path[0].Floor())
+	to := g.Level.GetCell(path[1].Floor())
+
+	ref, ok := DoorBetween(from, to)
+	if !ok {
+		p.doorStuck = 0
+		return
+	}
+
+	ref.Cell.Wake(g)
+	face := ref.Face()
+	if face.body == nil {
+		return
+	}
+
+	doorPos := Vec2FromCP(face.body.Position())
+	bodyPos := Vec2FromCP(p.body.Position())
+
+	if doorPos.Distance(bodyPos) > p.definition.BodyRadius+0.75 {
+		p.doorStuck = 0
+		return
+	}
+
+	direction := to.Position.Subtract(from.Position).To2D().Normalize()
+	push := direction.Scale(p.definition.DoorForce * face.body.Mass() * g.TimeDelta.Seconds())
+	face.body.ApplyImpulseAtWorldPoint(push.CP(), bodyPos.CP())
+	face.pushedBy = GroupMonster
+
+	if !p.definition.BatterDoors {
+		return
+	}
+
+	speed := Vec2FromCP(p.body.Velocity()).Length()
+	if speed > DOOR_STUCK_SPEED || math.Abs(face.body.AngularVelocity()) > DOOR_NOISE_VELOCITY {
+		p.doorStuck = 0
+		return
+	}
+
+	p.doorStuck += g.TimeDelta
+	if p.doorStuck < DOOR_STUCK_DELAY || (g.Time-p.batterAt).Seconds() < p.definition.BatterInterval {
+		return
+	}
+	p.batterAt = g.Time
+
+	p.BatterDoor(g, ref, direction)
+}
+
+func (p *Monster) BatterDoor(g *Game, ref FaceRef, direction Vec2) {
+	face := ref.Face()
+	pos := face.body.Position()
+
+	face.Damage += p.definition.BatterDamage
+	face.body.ApplyImpulseAtWorldPoint(direction.Scale(p.definition.BatterImpulse*face.body.Mass()).CP(), pos)
+	face.pushedBy = GroupMonster
+
+	noise := Noise{
+		Position: NewVec3(pos.X, ref.Cell.Position.Y, pos.Y),
+		Loudness: BATTER_LOUDNESS,
+		Source:   NoiseBatter,
+		Group:    GroupMonster,
+	}
+	g.EmitNoise(noise.Position, noise.Loudness, noise.Source, noise.Group)
+	g.Shake(g.Level.Audibility(noise, g.Player.Position3D()))
+
+	if face.Damage >= DOOR_HEALTH {
+		g.Level.BreakFace(g, ref)
+	}
+}
+
+// Shake jolts the camera. Amounts add up and fade over time.
+func (g *Game) Shake(amount float64) {
+	g.shake = math.Min(g.shake+amount, 1)
+}
+
+func (g *Game) UpdateShake() Vec3 {
+	g.shake *= math.Exp(-SHAKE_DECAY * g.TimeDelta.Seconds())
+
+	return NewVec3(rand.Float64()-0.5, rand.Float64()-0.5, rand.Float64()-0.5).Scale(g.shake * 0.3)
+}
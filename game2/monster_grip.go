@@ -0,0 +1,90 @@
+package game2
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/jakecoffman/cp"
+)
+
+func (ma *MonsterArm) Length() float64 {
+	length := 0.0
+	for _, segment := range ma.Segments {
+		length += segment.Length
+	}
+	return length
+}
+
+func (ma *MonsterArm) TipEnd() cp.Vector {
+	tip := ma.Tip()
+	return tip.body.LocalToWorld(cp.Vector{X: tip.Length / 2})
+}
+
+// GaitPhase runs from 0 to 1 over one gait cycle. Arms are spread evenly across the cycle so
+// some are always holding on while the others reach forward.
+func (ma *MonsterArm) GaitPhase(g *Game, definition *MonsterDefinition) float64 {
+	return math.Mod(g.Time.Seconds()/definition.GaitPeriod+ma.GaitAngle, 1)
+}
+
+// UpdateGrip latches and releases the arm and returns the pull it applies to the body.
+func (p *Monster) UpdateGrip(g *Game, arm *MonsterArm, direction Vec2) Vec2 {
+	bodyPos := Vec2FromCP(p.body.Position())
+
+	wantsGrip := direction.Length() > 0 && arm.GaitPhase(g, p.definition) < p.definition.GripFraction
+
+	if arm.grip != nil {
+		gripDelta := arm.GripPoint.Subtract(bodyPos)
+
+		if !wantsGrip || gripDelta.DotProduct(direction) < 0 || gripDelta.Length() > arm.Length() {
+			p.ReleaseGrip(g, arm)
+		}
+	} else if wantsGrip {
+		p.TryGrip(g, arm)
+	}
+
+	if arm.grip == nil {
+		return Vec2{}
+	}
+
+	return arm.GripPoint.Subtract(bodyPos).Normalize().Scale(p.definition.GripPull * p.body.Mass())
+}
+
+func (p *Monster) TryGrip(g *Game, arm *MonsterArm) {
+	filter := cp.NewShapeFilter(GroupMonster, Category(p.Y, true, false), Category(p.Y, true, false))
+
+	info := g.Space.PointQueryNearest(arm.TipEnd(), p.definition.GripReach, filter)
+
+	if info.Shape == nil || info.Shape.Body() != g.Space.StaticBody {
+		return
+	}
+
+	p.Grip(g, arm, Vec2FromCP(info.Point))
+}
+
+func (p *Monster) Grip(g *Game, arm *MonsterArm, point Vec2) {
+	tip := arm.Tip()
+
+	arm.grip = g.Space.AddConstraint(cp.NewPivotJoint2(tip.body, g.Space.StaticBody, cp.Vector{X: tip.Length / 2}, point.CP()))
+	arm.grip.SetMaxForce(p.definition.GripMaxForce)
+	arm.Gripping = true
+	arm.GripPoint = point
+}
+
+func (p *Monster) ReleaseGrip(g *Game, arm *MonsterArm) {
+	if arm.grip != nil {
+		g.Space.RemoveConstraint(arm.grip)
+	}
+	arm.grip = nil
+	arm.Gripping = false
+}
+
+func (p *Monster) DrawGrips(g *Game) {
+	for _, arm := range p.Arms {
+		if arm.grip == nil {
+			continue
+		}
+		tip := arm.Tip()
+		rl.DrawLine3D(Vec3From2D(Vec2FromCP(arm.TipEnd()), tip.Y).Raylib(), Vec3From2D(arm.GripPoint, tip.Y).Raylib(), rl.Orange)
+		rl.DrawSphere(Vec3From2D(arm.GripPoint, tip.Y).Raylib(), 0.05, rl.Orange)
+	}
+}
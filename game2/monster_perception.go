@@ -0,0 +1,126 @@
+package game2
+
+import (
+	"image/color"
+	"math"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/jakecoffman/cp"
+)
+
+const MONSTER_VIEW_DISTANCE = 10
+const MONSTER_VIEW_CONE_RADIANS = math.Pi / 2.5
+const MONSTER_CONFIDENCE_DECAY = 0.1
+const MONSTER_CONFIDENCE_PURSUE = 0.05
+
+type MonsterPerception struct {
+	Direction Vec2
+
+	CanSeePlayer            bool
+	LastKnownPlayerPosition Vec3
+	Confidence              float64
+
+	LastHeard time.Duration
+}
+
+func (mp *MonsterPerception) Update(g *Game, m *Monster) {
+	monsterPos := m.Position3D()
+
+	velocity := Vec2FromCP(m.body.Velocity())
+	if velocity.Length() > 0.1 {
+		mp.Direction = velocity.Normalize()
+	} else if mp.Direction.Length() == 0 {
+		mp.Direction = NewVec2(1, 0)
+	}
+
+	mp.Confidence = math.Max(0, mp.Confidence-g.TimeDelta.Seconds()*MONSTER_CONFIDENCE_DECAY)
+
+	mp.CanSeePlayer = mp.CanSee(g, m.definition, monsterPos, g.Player.Position3D())
+
+	if mp.CanSeePlayer && monsterPos.Distance(g.Player.Position3D()) > m.definition.DarkViewDistance && g.LightLevel(g.Player.Position3D()) < LIGHT_LEVEL_DARK {
+		mp.CanSeePlayer = false
+	}
+
+	// A flashlight gives the player away both to whoever stands in its beam and to whoever
+	// sees the spot it lights up.
+	if beam, ok := g.Player.Flashlight(); ok && !mp.CanSeePlayer {
+		if beam.Illuminates(monsterPos) && g.LineOfSight(beam.Position, monsterPos) {
+			mp.CanSeePlayer = true
+		} else if mp.CanSee(g, m.definition, monsterPos, beam.Spot(g)) && mp.Confidence < FLASHLIGHT_NOTICE_CONFIDENCE {
+			mp.LastKnownPlayerPosition = g.Player.Position3D()
+			mp.Confidence = FLASHLIGHT_NOTICE_CONFIDENCE
+		}
+	}
+
+	if mp.CanSeePlayer {
+		mp.LastKnownPlayerPosition = g.Player.Position3D()
+		mp.Confidence = 1
+	}
+
+	for _, noise := range g.NoisesSince(mp.LastHeard) {
+		if noise.Group == GroupMonster {
+			continue
+		}
+
+		audibility := g.Level.Audibility(noise, monsterPos)
+
+		if audibility > 0 && audibility >= mp.Confidence {
+			mp.LastKnownPlayerPosition = noise.Position
+			mp.Confidence = audibility
+		}
+	}
+	mp.LastHeard = g.Time
+}
+
+func (mp *MonsterPerception) CanSee(g *Game, definition *MonsterDefinition, from Vec3, to Vec3) bool {
+	if math.Floor(from.Y) != math.Floor(to.Y) {
+		return false
+	}
+
+	delta := to.To2D().Subtract(from.To2D())
+	distance := delta.Length()
+
+	if distance > definition.ViewDistance {
+		return false
+	}
+
+	if distance > 0 && math.Acos(Clamp(mp.Direction.DotProduct(delta.Scale(1/distance)), -1, 1)) > definition.ViewConeRadians/2 {
+		return false
+	}
+
+	return g.LineOfSight(from, to)
+}
+
+// LineOfSight tells whether no wall stands between two points on the same floor.
+func (g *Game) LineOfSight(from Vec3, to Vec3) bool {
+	filter := cp.NewShapeFilter(0, Category(from.Y, true, false), Category(from.Y, true, false))
+	result := g.Space.SegmentQueryFirst(from.To2D().CP(), to.To2D().CP(), 0, filter)
+
+	return result.Shape == nil
+}
+
+func (mp *MonsterPerception) HasTarget() bool {
+	return mp.Confidence > MONSTER_CONFIDENCE_PURSUE
+}
+
+func (mp *MonsterPerception) Draw3D(g *Game, m *Monster) {
+	monsterPos := m.Position3D().Add(Y.Scale(0.5))
+	angle := math.Atan2(mp.Direction.Y, mp.Direction.X)
+
+	col := color.RGBA{255, 255, 0, 100}
+	if mp.CanSeePlayer {
+		col = color.RGBA{255, 0, 0, 150}
+	}
+
+	for _, side := range []float64{-1, 1} {
+		a := angle + side*m.definition.ViewConeRadians/2
+		edge := monsterPos.Add(NewVec3(math.Cos(a), 0, math.Sin(a)).Scale(m.definition.ViewDistance))
+		rl.DrawLine3D(monsterPos.Raylib(), edge.Raylib(), col)
+	}
+
+	if mp.HasTarget() {
+		target := mp.LastKnownPlayerPosition.Add(Y.Scale(0.5))
+		rl.DrawSphere(target.Raylib(), 0.1, color.RGBA{255, 0, 255, uint8(255 * mp.Confidence)})
+	}
+}
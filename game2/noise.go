@@ -0,0 +1,221 @@
+package game2
+
+import (
+	"container/heap"
+	"image/color"
+	"math"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/jakecoffman/cp"
+)
+
+const NOISE_LIFETIME = time.Second
+const SOUND_DOOR_ATTENUATION = 3.0
+const SOUND_WINDOW_ATTENUATION = 4.0
+
+const FOOTSTEP_STRIDE = 0.6
+const FOOTSTEP_LOUDNESS = 1.5
+const DOOR_NOISE_VELOCITY = 1.0
+const DOOR_NOISE_INTERVAL = time.Second / 4
+const DOOR_LOUDNESS = 2.0
+const IMPACT_NOISE_SPEED = 1.2
+const IMPACT_LOUDNESS = 1.7
+const ITEM_DROP_LOUDNESS = 1.0
+
+type NoiseSource = uint8
+
+const (
+	NoiseFootstep = NoiseSource(iota)
+	NoiseDoor
+	NoiseImpact
+	NoiseBatter
+)
+
+// Loudness is the distance in cells at which a noise fades to nothing in open space.
+// Group is the physics group of whatever made the noise, so listeners can ignore themselves.
+type Noise struct {
+	Position Vec3
+	Loudness float64
+	Time     time.Duration
+	Source   NoiseSource
+	Group    uint
+}
+
+func (g *Game) EmitNoise(position Vec3, loudness float64, source NoiseSource, group uint) {
+	if loudness <= 0 {
+		return
+	}
+	g.Noises = append(g.Noises, Noise{
+		Position: position,
+		Loudness: loudness,
+		Time:     g.Time,
+		Source:   source,
+		Group:    group,
+	})
+}
+
+// NoisesSince returns the noises emitted after the given time, oldest first.
+func (g *Game) NoisesSince(since time.Duration) []Noise {
+	for i, noise := range g.Noises {
+		if noise.Time > since {
+			return g.Noises[i:]
+		}
+	}
+	return nil
+}
+
+// UpdateDoorNoise makes swinging doors audible. The noise belongs to whoever last pushed the
+// door, so monsters don't chase doors they swung themselves.
+func (g *Game) UpdateDoorNoise() {
+	for _, ref := range g.Level.doors {
+		face := ref.Face()
+		face.body.EachArbiter(func(arb *cp.Arbiter) {
+			_, other := arb.Shapes()
+			if group := other.Filter.Group; group != 0 && group != GroupStatic {
+				face.pushedBy = group
+			}
+		})
+
+		angularVelocity := math.Abs(face.body.AngularVelocity())
+
+		if angularVelocity < DOOR_NOISE_VELOCITY || g.Time-face.lastNoise < DOOR_NOISE_INTERVAL {
+			continue
+		}
+		face.lastNoise = g.Time
+
+		pos := face.body.Position()
+		group := face.pushedBy
+		if group == 0 {
+			group = GroupStatic
+		}
+		g.EmitNoise(NewVec3(pos.X, ref.Cell.Position.Y, pos.Y), angularVelocity*DOOR_LOUDNESS, NoiseDoor, group)
+	}
+}
+
+func (g *Game) DrawNoises() {
+	for _, noise := range g.Noises {
+		age := float64(g.Time-noise.Time) / float64(NOISE_LIFETIME)
+
+		col := color.RGBA{255, 255, 255, uint8(255 * (1 - age))}
+		switch noise.Source {
+		case NoiseFootstep:
+			col.B = 0
+		case NoiseDoor:
+			col.R = 0
+		case NoiseImpact:
+			col.G = 0
+		case NoiseBatter:
+			col.G, col.B = 0, 0
+		}
+
+		center := noise.Position.Add(Y.Scale(0.05))
+		rl.DrawCircle3D(center.Raylib(), float32(noise.Loudness*age), X.Raylib(), 90, col)
+	}
+}
+
+func (g *Game) pruneNoises() {
+	noises := g.Noises[:0]
+	for _, noise := range g.Noises {
+		if g.Time-noise.Time < NOISE_LIFETIME {
+			noises = append(noises, noise)
+		}
+	}
+	g.Noises = noises
+}
+
+// Audibility returns how loud the noise is at the listener, from 1 at the source down to 0,
+// following open paths through the level and losing loudness at every door and window.
+func (l *Level) Audibility(noise Noise, listener Vec3) float64 {
+	distance, ok := l.SoundDistance(noise.Position, listener, noise.Loudness)
+	if !ok {
+		return 0
+	}
+	return 1 - distance/noise.Loudness
+}
+
+func (l *Level) SoundDistance(from Vec3, to Vec3, maxDistance float64) (float64, bool) {
+	start := l.PeekCell(from)
+	end := l.PeekCell(to)
+	if start == nil || end == nil {
+		return maxDistance, false
+	}
+	if start == end {
+		distance := from.Distance(to)
+		return math.Min(distance, maxDistance), distance < maxDistance
+	}
+
+	distances := map[*Cell]float64{start: 0}
+	queue := &soundQueue{{start, 0}}
+
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(soundNode)
+
+		if current.cell == end {
+			offset := from.To2D().Distance(start.Center2D()) + end.Center2D().Distance(to.To2D())
+			return math.Min(current.distance+offset, maxDistance), current.distance+offset < maxDistance
+		}
+		if current.distance > distances[current.cell] {
+			continue
+		}
+
+		for _, next := range l.soundNeighbors(current.cell) {
+			distance := current.distance + current.cell.PathNeighborCost(next)
+
+			face, other := current.cell.FaceTowards(next).Type, next.FaceTowards(current.cell).Type
+			if face == FaceWindow || other == FaceWindow {
+				distance += SOUND_WINDOW_ATTENUATION
+			} else if face == FaceDoor || other == FaceDoor {
+				distance += SOUND_DOOR_ATTENUATION
+			}
+
+			if distance >= maxDistance {
+				continue
+			}
+			if known, ok := distances[next]; ok && known <= distance {
+				continue
+			}
+			distances[next] = distance
+			heap.Push(queue, soundNode{next, distance})
+		}
+	}
+
+	return maxDistance, false
+}
+
+// soundNeighbors are the path neighbours of c plus the cells behind its windows, as sound
+// passes through the glass where nothing else can.
+func (l *Level) soundNeighbors(c *Cell) []*Cell {
+	neighbors := c.Neighbors(l.PeekCell)
+
+	for FACE := range FACES {
+		next := l.PeekCell(c.Position.Add(FACE_DIRECTION[FACE]))
+		if next == nil {
+			continue
+		}
+
+		face, other := c.Faces[FACE].Type, next.Faces[FACE_OPPOSITE[FACE]].Type
+		if (face == FaceWindow || other == FaceWindow) && face != FaceWall && other != FaceWall {
+			neighbors = append(neighbors, next)
+		}
+	}
+	return neighbors
+}
+
+type soundNode struct {
+	cell     *Cell
+	distance float64
+}
+
+type soundQueue []soundNode
+
+func (q soundQueue) Len() int           { return len(q) }
+func (q soundQueue) Less(i, j int) bool { return q[i].distance < q[j].distance }
+func (q soundQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *soundQueue) Push(x any)        { *q = append(*q, x.(soundNode)) }
+func (q *soundQueue) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
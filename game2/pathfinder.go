@@ -13,6 +13,7 @@ type PathFinder struct {
 
 	PathLength float64
 	Path       []Vec3
+	Rules      PathRules
 	level      *Level
 }
 
@@ -34,8 +35,8 @@ func (p *PathFinder) SetPosition(position Vec3) {
 func (p *PathFinder) SetTarget(position Vec3) {
 	p.Target = position
 
-	start := p.level.GetCell(p.Position)
-	end := p.level.GetCell(p.Target.Floor())
+	start := pathNode{p.level.GetCell(p.Position), &p.Rules}
+	end := pathNode{p.level.GetCell(p.Target.Floor()), &p.Rules}
 
 	pathers, length, found := astar.Path(end, start)
 
@@ -50,8 +51,7 @@ func (p *PathFinder) SetTarget(position Vec3) {
 	cells := make([]*Cell, len(pathers))
 
 	for i, p := range pathers {
-		cell := p.(*Cell)
-		cells[i] = cell
+		cells[i] = p.(pathNode).cell
 	}
 
 	path := make([]Vec3, len(pathers))
@@ -64,6 +64,23 @@ func (p *PathFinder) SetTarget(position Vec3) {
 
 }
 
+// SteeringTarget is the point to head for along the path. Between cells on the same floor
+// that is the shared edge, but across a stair it is the next cell itself so the body
+// commits to the climb instead of hugging the step.
+func (p *PathFinder) SteeringTarget() (Vec3, bool) {
+	if p.Idle || len(p.Path) < 2 {
+		return Vec3{}, false
+	}
+
+	a := p.Path[0]
+	b := p.Path[1]
+
+	if a.Y != b.Y {
+		return b, true
+	}
+	return b.Lerp(a, 0.5), true
+}
+
 func (p *PathFinder) Draw3D(g *Game) {
 
 	for i := 0; i < len(p.Path)-2; i++ {
@@ -118,36 +118,18 @@ func UpdatePhysicsY(g *Game, shape *cp.Shape, y float64, yVelocity float64) (flo
 	bodyPosition := shape.Body().Position()
 	pos := NewVec3(bodyPosition.X, y, bodyPosition.Y)
 
-	cell := g.Level.GetCell(pos)
-
-	var groundY float64
-
-	switch cell.Ground.Type {
-	case GroundStair:
-		x := math.Ceil(pos.X) - pos.X
-		z := pos.Z - math.Floor(pos.Z)
-
-		switch cell.Ground.StairDirection {
-		case FACE_EAST:
-			groundY = cell.Position.Y + x
-		case FACE_NORTH:
-			groundY = cell.Position.Y + z
-		case FACE_WEST:
-			groundY = cell.Position.Y + 1 - x
-		case FACE_SOUTH:
-			groundY = cell.Position.Y + 1 - z
-		}
-	case GroundFloor:
-		groundY = cell.Position.Y
-	case GroundEmpty:
-		groundY = 0
-	}
+	groundY := g.Level.GroundHeight(pos)
 
 	if y > groundY {
 		yVelocity -= g.TimeDelta.Seconds() / 5
 	}
 
 	if y+yVelocity < groundY {
+		// yVelocity is moved per frame, so the landing speed is taken per second to keep the
+		// noise the same at any frame rate.
+		if dt := g.TimeDelta.Seconds(); dt > 0 && -yVelocity/dt > IMPACT_NOISE_SPEED {
+			g.EmitNoise(NewVec3(pos.X, groundY, pos.Z), -yVelocity/dt*IMPACT_LOUDNESS, NoiseImpact, shape.Filter.Group)
+		}
 		y = groundY
 		yVelocity = 0
 	}
@@ -160,10 +142,108 @@ func UpdatePhysicsY(g *Game, shape *cp.Shape, y float64, yVelocity float64) (flo
 		y = math.Ceil(y)
 	}
 
+	SetFloorFilter(shape, y)
+
+	return y, yVelocity
+}
+
+// SetFloorFilter makes the shape collide with the walls and entities of the floor at y.
+func SetFloorFilter(shape *cp.Shape, y float64) {
 	shape.Filter.Categories = Category(y, false, true)
 	shape.Filter.Mask = Category(y, true, true)
+}
 
-	return y, yVelocity
+// GroundHeight searches down the column from pos for the first cell with ground and returns
+// the height of its surface at pos. Without any ground below, the bottom of the level is used.
+// An elevator car below pos counts as ground too.
+func (l *Level) GroundHeight(pos Vec3) float64 {
+	ground := l.cellGroundHeight(pos)
+
+	if e := l.ElevatorAt(pos); e != nil && e.Y <= pos.Y+ELEVATOR_RIDE_TOLERANCE {
+		ground = max(ground, e.Y)
+	}
+
+	return ground
+}
+
+func (l *Level) cellGroundHeight(pos Vec3) float64 {
+	for y := math.Floor(pos.Y); y >= 0; y-- {
+		cell := l.PeekCell(NewVec3(math.Floor(pos.X), y, math.Floor(pos.Z)))
+		if cell == nil {
+			continue
+		}
+
+		switch cell.Ground.Type {
+		case GroundStair:
+			x := math.Ceil(pos.X) - pos.X
+			z := pos.Z - math.Floor(pos.Z)
+
+			switch cell.Ground.StairDirection {
+			case FACE_EAST:
+				return cell.Position.Y + x
+			case FACE_NORTH:
+				return cell.Position.Y + z
+			case FACE_WEST:
+				return cell.Position.Y + 1 - x
+			case FACE_SOUTH:
+				return cell.Position.Y + 1 - z
+			}
+		case GroundFloor, GroundLadder:
+			return cell.Position.Y
+		case GroundEmpty:
+			if cell.IsLadderLanding() {
+				return cell.Position.Y
+			}
+		}
+	}
+
+	return 0
+}
+
+type BodyState struct {
+	Position        Vec2
+	Velocity        Vec2
+	Angle           float64
+	AngularVelocity float64
+}
+
+func NewBodyState(body *cp.Body) BodyState {
+	return BodyState{
+		Position:        Vec2FromCP(body.Position()),
+		Velocity:        Vec2FromCP(body.Velocity()),
+		Angle:           body.Angle(),
+		AngularVelocity: body.AngularVelocity(),
+	}
+}
+
+func (s BodyState) Apply(body *cp.Body) {
+	body.SetPosition(s.Position.CP())
+	body.SetVelocity(s.Velocity.X, s.Velocity.Y)
+	body.SetAngle(s.Angle)
+	body.SetAngularVelocity(s.AngularVelocity)
+}
+
+// RemoveBody removes the body from the space along with its shapes and every constraint attached to it.
+func RemoveBody(space *cp.Space, body *cp.Body) {
+	constraints := []*cp.Constraint{}
+	body.EachConstraint(func(c *cp.Constraint) {
+		constraints = append(constraints, c)
+	})
+	for _, c := range constraints {
+		if space.ContainsConstraint(c) {
+			space.RemoveConstraint(c)
+		}
+	}
+
+	shapes := []*cp.Shape{}
+	body.EachShape(func(s *cp.Shape) {
+		shapes = append(shapes, s)
+	})
+	for _, s := range shapes {
+		space.RemoveShape(s)
+	}
+
+	space.RemoveBody(body)
 }
 
 const (
@@ -0,0 +1,40 @@
+package game2
+
+type FaceKey struct {
+	Cell Vec3
+	Face FaceIndex
+}
+
+// PhysicsSnapshot holds the dynamic bodies owned by level cells. Actors save their own
+// bodies through PlayerSave and Entity.Save.
+type PhysicsSnapshot struct {
+	Faces map[FaceKey]BodyState
+}
+
+func (g *Game) PhysicsSnapshot() PhysicsSnapshot {
+	snapshot := PhysicsSnapshot{
+		Faces: make(map[FaceKey]BodyState, len(g.Level.doors)),
+	}
+
+	for _, ref := range g.Level.doors {
+		face := ref.Face()
+		if face.body == nil {
+			continue
+		}
+		snapshot.Faces[FaceKey{ref.Cell.Position, ref.Index}] = NewBodyState(face.body)
+	}
+
+	return snapshot
+}
+
+func (s PhysicsSnapshot) Apply(g *Game) {
+	for key, state := range s.Faces {
+		cell := g.Level.GetCell(key.Cell)
+		cell.Wake(g)
+
+		face := &cell.Faces[key.Face]
+		if face.body != nil && face.body != g.Space.StaticBody {
+			state.Apply(face.body)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package game2
 import (
 	"image/color"
 	"math"
+	"time"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 	"github.com/jakecoffman/cp"
@@ -19,10 +20,30 @@ type Player struct {
 	body      *cp.Body
 	shape     *cp.Shape
 
+	Health            float64
+	HealthMax         float64
+	SpO2              float64
+	Dead              bool
+	Checkpoint        Vec3
+	HasCheckpoint     bool
+	Items             []Item
+	dropItem          Item
+	diedAt            time.Duration
+	invulnerableUntil time.Duration
+
+	Mode      MovementMode
+	Movement  PlayerMovement
+	Stamina   float64
+	exhausted bool
+	Climbing  bool
+
 	visibilityVerts [VISIBILITY_VERTS]Vec3
+	viewDistance    float64
+	stride          float64
 
-	ViewTexture  rl.RenderTexture2D
-	LookPosition Vec3
+	ViewTexture    rl.RenderTexture2D
+	LookPosition   Vec3
+	interactTarget Interactable
 }
 
 func (p *Player) Update(g *Game) {
@@ -41,16 +62,45 @@ func (p *Player) Update(g *Game) {
 		force = force.Add(cp.Vector{Y: 1})
 	}
 
+	climbing := p.UpdateClimb(g)
+	if climbing {
+		force.Y = 0
+	}
+
+	if p.Dead {
+		force = cp.Vector{}
+	}
+
 	forceMag := force.Length()
+	movement := p.UpdateMovementMode(g, forceMag != 0)
 
 	if forceMag != 0 {
-		force = force.Normalize().Mult(4)
+		force = force.Normalize().Mult(movement.Speed)
 	}
 
-	newVelocity := p.body.Velocity().Lerp(force, 0.1)
+	newVelocity := p.body.Velocity().Lerp(force, movement.Acceleration)
 	p.body.SetVelocity(newVelocity.X, newVelocity.Y)
 
-	p.Y, p.YVelocity = UpdatePhysicsY(g, p.shape, p.Y, p.YVelocity)
+	if climbing {
+		SetFloorFilter(p.shape, p.Y)
+	} else {
+		yVelocity := p.YVelocity
+		p.Y, p.YVelocity = UpdatePhysicsY(g, p.shape, p.Y, p.YVelocity)
+		p.UpdateFall(g, yVelocity)
+	}
+	p.UpdateHealth(g)
+	p.UpdateInteraction(g)
+	p.UpdateItems(g)
+	p.UpdateVitals(g)
+
+	speed := newVelocity.Length()
+	if p.YVelocity == 0 {
+		p.stride += speed * g.TimeDelta.Seconds()
+	}
+	if p.stride >= FOOTSTEP_STRIDE {
+		p.stride = 0
+		g.EmitNoise(p.Position3D(), speed*FOOTSTEP_LOUDNESS*movement.NoiseScale, NoiseFootstep, GroupPlayer)
+	}
 
 	if math.Abs(g.MouseRayDirection.Y) >= 1e-6 {
 		t := (p.Y - g.MouseRayOrigin.Y) / g.MouseRayDirection.Y
@@ -81,7 +131,7 @@ func (p *Player) Update(g *Game) {
 		angleOffset := f*VISIBILITY_CONE_RADIANS - math.Pi
 		angle := baseAngle - angleOffset
 		dir := NewVec2(math.Cos(angle), math.Sin(angle))
-		to := from.Add(dir.Scale(VISIBILITY_DISTANCE))
+		to := from.Add(dir.Scale(p.viewDistance))
 
 		result := g.Space.SegmentQueryFirst(from.CP(), to.CP(), 0, cp.NewShapeFilter(0, Category(p.Y, true, false), Category(p.Y, true, false)))
 
@@ -94,14 +144,44 @@ func (p *Player) Position3D() Vec3 {
 }
 
 type PlayerSave struct {
-	Position Vec2
-	Y        float64
+	// Position is only set in saves from before Body was kept, Load moves it into Body.
+	Position  Vec2
+	Body      BodyState
+	Y         float64
+	YVelocity float64
+	Stamina   float64
+	Movement  PlayerMovement
+	Climbing  bool
+
+	Health        float64
+	HealthMax     float64
+	SpO2          float64
+	Dead          bool
+	Checkpoint    Vec3
+	HasCheckpoint bool
+	Items         []Item
 }
 
 func (p *Player) ToSave(g *Game) PlayerSave {
+	for _, item := range p.Items {
+		item.Save(g)
+	}
+
 	return PlayerSave{
-		Position: Vec2FromCP(p.body.Position()),
-		Y:        p.Y,
+		Body:      NewBodyState(p.body),
+		Y:         p.Y,
+		YVelocity: p.YVelocity,
+		Stamina:   p.Stamina,
+		Movement:  p.Movement,
+		Climbing:  p.Climbing,
+
+		Health:        p.Health,
+		HealthMax:     p.HealthMax,
+		SpO2:          p.SpO2,
+		Dead:          p.Dead,
+		Checkpoint:    p.Checkpoint,
+		HasCheckpoint: p.HasCheckpoint,
+		Items:         p.Items,
 	}
 }
 
@@ -109,17 +189,53 @@ func (save PlayerSave) Load(g *Game) *Player {
 	p := &Player{
 		Radius:      0.25,
 		Y:           save.Y,
+		YVelocity:   save.YVelocity,
 		body:        nil,
 		ViewTexture: rl.LoadRenderTexture(16*40, 16*40),
+
+		Stamina:      save.Stamina,
+		Movement:     save.Movement,
+		Climbing:     save.Climbing,
+		viewDistance: VISIBILITY_DISTANCE,
+
+		Health:        save.Health,
+		HealthMax:     save.HealthMax,
+		SpO2:          save.SpO2,
+		Dead:          save.Dead,
+		Checkpoint:    save.Checkpoint,
+		HasCheckpoint: save.HasCheckpoint,
+		Items:         save.Items,
+	}
+
+	if p.HealthMax == 0 {
+		p.HealthMax = PLAYER_HEALTH
+		p.Health = p.HealthMax
+	}
+
+	if p.SpO2 == 0 && !p.Dead {
+		p.SpO2 = PLAYER_SPO2
+	}
+
+	if p.Items == nil {
+		p.Items = DefaultPlayerItems()
+	}
+
+	if p.Movement.StaminaMax == 0 {
+		p.Movement = DefaultPlayerMovement()
+		p.Stamina = p.Movement.StaminaMax
 	}
 
 	mass := p.Radius * p.Radius * 4
 	body := g.Space.AddBody(cp.NewBody(mass, cp.MomentForCircle(mass, 0, p.Radius, Vec2{2, 2}.CP())))
-	body.SetPosition(save.Position.CP())
+	if save.Body == (BodyState{}) {
+		save.Body.Position = save.Position
+	}
+	save.Body.Apply(body)
 	p.shape = g.Space.AddShape(cp.NewCircle(body, p.Radius, Vec2{}.CP()))
 	p.shape.SetElasticity(0)
 	p.shape.SetFriction(0)
 	p.shape.Filter.Group = GroupPlayer
+	p.shape.SetCollisionType(CollisionPlayer)
 
 	p.body = body
 
@@ -129,6 +245,11 @@ func (save PlayerSave) Load(g *Game) *Player {
 }
 
 func (p *Player) Draw(g *Game) {
+	p.DrawInteraction(g)
+
+	if p.IsInvulnerable(g) && int(g.Time/(time.Second/10))%2 == 0 {
+		return
+	}
 	rl.DrawSphere(g.Player.Position3D().Add(Y.Scale(g.Player.Radius)).Raylib(), float32(g.Player.Radius), rl.Red)
 }
 
@@ -0,0 +1,130 @@
+package game2
+
+import (
+	"time"
+
+	"github.com/gen2brain/raylib-go/raygui"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const PLAYER_HEALTH = 100
+const PLAYER_INVULNERABILITY = time.Second
+const PLAYER_KNOCKBACK = 6
+const PLAYER_RESPAWN_DELAY = 3 * time.Second
+
+// Landing faster than PLAYER_FALL_SPEED units per second hurts, which about one floor of
+// falling reaches, by PLAYER_FALL_DAMAGE for every unit per second beyond it.
+const PLAYER_FALL_SPEED = 3.6
+const PLAYER_FALL_DAMAGE = 25
+
+func (p *Player) Hurt(g *Game, damage float64, direction Vec2) {
+	if p.Dead || g.Time < p.invulnerableUntil {
+		return
+	}
+
+	p.invulnerableUntil = g.Time + PLAYER_INVULNERABILITY
+
+	velocity := Vec2FromCP(p.body.Velocity()).Add(direction.Normalize().Scale(PLAYER_KNOCKBACK))
+	p.body.SetVelocity(velocity.X, velocity.Y)
+
+	p.Damage(g, damage)
+}
+
+// Damage takes health without knockback or invulnerability, for harm that builds up over time.
+func (p *Player) Damage(g *Game, damage float64) {
+	if p.Dead {
+		return
+	}
+
+	p.Health -= damage
+
+	if p.Health <= 0 {
+		p.Health = 0
+		p.Dead = true
+		p.diedAt = g.Time
+	}
+}
+
+// UpdateFall hurts the player by the YVelocity they landed with this frame. YVelocity is moved
+// per frame, so it is taken per second to hurt the same at any frame rate. Landing hard hurts
+// even right after a hit, so it skips invulnerability.
+func (p *Player) UpdateFall(g *Game, yVelocity float64) {
+	dt := g.TimeDelta.Seconds()
+	if p.YVelocity != 0 || dt <= 0 {
+		return
+	}
+
+	if speed := -yVelocity / dt; speed > PLAYER_FALL_SPEED {
+		p.Damage(g, (speed-PLAYER_FALL_SPEED)*PLAYER_FALL_DAMAGE)
+	}
+}
+
+func (p *Player) UpdateHealth(g *Game) {
+	cell := g.Level.GetCell(p.Position3D().Floor())
+	if cell.Ground.Checkpoint {
+		p.Checkpoint = cell.Position
+		p.HasCheckpoint = true
+	}
+
+	if p.Dead && g.Time-p.diedAt >= PLAYER_RESPAWN_DELAY {
+		p.Respawn(g)
+	}
+}
+
+func (p *Player) Respawn(g *Game) {
+	checkpoint := p.Checkpoint
+	if !p.HasCheckpoint {
+		checkpoint, _ = g.Level.FindCheckpoint(p.Position3D())
+	}
+
+	p.body.SetPosition(checkpoint.AddXYZ(0.5, 0, 0.5).Chipmunk())
+	p.body.SetVelocity(0, 0)
+	p.Y = checkpoint.Y
+	p.YVelocity = 0
+
+	p.Health = p.HealthMax
+	p.SpO2 = PLAYER_SPO2
+	p.Dead = false
+	p.invulnerableUntil = g.Time + PLAYER_INVULNERABILITY
+}
+
+func (p *Player) IsInvulnerable(g *Game) bool {
+	return g.Time < p.invulnerableUntil
+}
+
+func (p *Player) DrawHealthHUD(g *Game, line *LineLayout) {
+	raygui.ProgressBar(line.Next(200), "", "Health", float32(p.Health), 0, float32(p.HealthMax))
+
+	if p.Dead {
+		text := "You died"
+		fontSize := int32(40)
+		width := rl.MeasureText(text, fontSize)
+		rl.DrawText(text, int32(rl.GetRenderWidth())/2-width/2, int32(rl.GetRenderHeight())/2-fontSize/2, fontSize, rl.Red)
+	}
+}
+
+// FindCheckpoint returns the checkpoint cell closest to pos, or the origin if the level has none.
+func (l *Level) FindCheckpoint(pos Vec3) (Vec3, bool) {
+	closest := Vec3{}
+	found := false
+
+	for _, chunk := range l.Chunks {
+		for x := range CHUNK_WIDTH {
+			for z := range CHUNK_WIDTH {
+				for y := range CHUNK_HEIGHT {
+					cell := &chunk[x][z][y]
+
+					if !cell.Ground.Checkpoint {
+						continue
+					}
+					if !found || cell.Position.Distance(pos) < closest.Distance(pos) {
+						closest = cell.Position
+						found = true
+					}
+				}
+			}
+		}
+	}
+
+	return closest, found
+}
@@ -0,0 +1,98 @@
+package game2
+
+import (
+	"github.com/gen2brain/raylib-go/raygui"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+type MovementMode = uint8
+
+const (
+	MovementWalk = MovementMode(iota)
+	MovementSprint
+	MovementCrouch
+	MOVEMENT_MODES
+)
+
+type MovementConfig struct {
+	Speed              float64
+	Acceleration       float64
+	NoiseScale         float64
+	VisibilityDistance float64
+	StaminaRate        float64
+}
+
+type PlayerMovement struct {
+	Modes [MOVEMENT_MODES]MovementConfig
+
+	StaminaMax float64
+	// Stamina needed before sprinting is allowed again after running out.
+	StaminaRecovered float64
+}
+
+func DefaultPlayerMovement() PlayerMovement {
+	return PlayerMovement{
+		Modes: [MOVEMENT_MODES]MovementConfig{
+			MovementWalk: {
+				Speed:              4,
+				Acceleration:       0.1,
+				NoiseScale:         1,
+				VisibilityDistance: VISIBILITY_DISTANCE,
+				StaminaRate:        15,
+			},
+			MovementSprint: {
+				Speed:              7,
+				Acceleration:       0.08,
+				NoiseScale:         2,
+				VisibilityDistance: VISIBILITY_DISTANCE * 0.75,
+				StaminaRate:        -25,
+			},
+			MovementCrouch: {
+				Speed:              1.5,
+				Acceleration:       0.15,
+				NoiseScale:         0.2,
+				VisibilityDistance: VISIBILITY_DISTANCE * 0.9,
+				StaminaRate:        20,
+			},
+		},
+		StaminaMax:       100,
+		StaminaRecovered: 30,
+	}
+}
+
+func (p *Player) UpdateMovementMode(g *Game, moving bool) MovementConfig {
+	p.Mode = MovementWalk
+
+	if rl.IsKeyDown(rl.KeyLeftControl) {
+		p.Mode = MovementCrouch
+	} else if rl.IsKeyDown(rl.KeyLeftShift) && moving && !p.exhausted {
+		p.Mode = MovementSprint
+	}
+
+	config := p.Movement.Modes[p.Mode]
+
+	p.Stamina = Clamp(p.Stamina+config.StaminaRate*g.TimeDelta.Seconds(), 0, p.Movement.StaminaMax)
+
+	if p.Stamina == 0 {
+		p.exhausted = true
+	} else if p.Stamina >= p.Movement.StaminaRecovered {
+		p.exhausted = false
+	}
+
+	p.viewDistance = Lerp(p.viewDistance, config.VisibilityDistance, 0.05)
+
+	return config
+}
+
+func (p *Player) DrawHUD(g *Game) {
+	size := float64(20)
+	line := NewLineLayout(10, float64(rl.GetRenderHeight())-size-10, size)
+
+	raygui.ProgressBar(line.Next(200), "", "Stamina", float32(p.Stamina), 0, float32(p.Movement.StaminaMax))
+	line.Next(70)
+	p.DrawHealthHUD(g, line)
+	line.Next(70)
+	p.DrawVitalsHUD(g, line)
+	p.DrawInventoryHUD(g)
+	p.DrawInteractionHUD(g)
+}
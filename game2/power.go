@@ -0,0 +1,296 @@
+package game2
+
+import (
+	"image/color"
+	"math"
+	"slices"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/jakecoffman/cp"
+)
+
+type PowerKind = uint8
+
+const (
+	PowerSource = PowerKind(iota)
+	PowerSwitch
+	PowerLight
+	PowerDoor
+	PowerElevator
+)
+
+var POWER_KIND_NAMES = [...]string{"source", "switch", "light", "door", "elevator"}
+
+// A network is powered while its running sources supply at least what its consumers draw.
+// Overloaded networks go dark as a whole.
+const POWER_SOURCE_CAPACITY = 10.0
+
+var POWER_LOAD = [...]float64{0, 0, 1, 2, 5}
+
+type PowerID = uint64
+
+// PowerNode sits in a cell of the level. Sources supply and switches conduct only while On;
+// doors are identified by their cell and Face.
+type PowerNode struct {
+	ID       PowerID
+	Kind     PowerKind
+	Position Vec3
+	Face     FaceIndex
+	On       bool
+
+	powered bool
+}
+
+type Wire struct {
+	A PowerID
+	B PowerID
+}
+
+// PowerNetwork is saved with the level. Equipment that has no node wired to it keeps running
+// as if there were no network at all.
+type PowerNetwork struct {
+	Nodes  []*PowerNode
+	Wires  []Wire
+	NextID PowerID
+}
+
+func (n *PowerNetwork) Add(node *PowerNode) *PowerNode {
+	n.NextID++
+	node.ID = n.NextID
+	n.Nodes = append(n.Nodes, node)
+	return node
+}
+
+func (n *PowerNetwork) Remove(id PowerID) {
+	n.Nodes = slices.DeleteFunc(n.Nodes, func(node *PowerNode) bool {
+		return node.ID == id
+	})
+	n.Wires = slices.DeleteFunc(n.Wires, func(wire Wire) bool {
+		return wire.A == id || wire.B == id
+	})
+}
+
+func (n *PowerNetwork) Node(id PowerID) *PowerNode {
+	for _, node := range n.Nodes {
+		if node.ID == id {
+			return node
+		}
+	}
+	return nil
+}
+
+// Connect wires two nodes together, or cuts the wire if they already are.
+func (n *PowerNetwork) Connect(a PowerID, b PowerID) {
+	if a == b {
+		return
+	}
+
+	index := slices.IndexFunc(n.Wires, func(wire Wire) bool {
+		return (wire.A == a && wire.B == b) || (wire.A == b && wire.B == a)
+	})
+	if index >= 0 {
+		n.Wires = slices.Delete(n.Wires, index, index+1)
+		return
+	}
+	n.Wires = append(n.Wires, Wire{a, b})
+}
+
+func (node *PowerNode) Conducts() bool {
+	return node.Kind != PowerSwitch || node.On
+}
+
+func (node *PowerNode) Powered() bool {
+	return node.powered
+}
+
+// Solve finds the groups of nodes connected through closed switches and powers each group
+// that has enough supply for its load.
+func (n *PowerNetwork) Solve() {
+	neighbors := make(map[PowerID][]*PowerNode, len(n.Nodes))
+	for _, wire := range n.Wires {
+		a, b := n.Node(wire.A), n.Node(wire.B)
+		if a == nil || b == nil || !a.Conducts() || !b.Conducts() {
+			continue
+		}
+		neighbors[a.ID] = append(neighbors[a.ID], b)
+		neighbors[b.ID] = append(neighbors[b.ID], a)
+	}
+
+	visited := make(map[PowerID]bool, len(n.Nodes))
+
+	for _, start := range n.Nodes {
+		if visited[start.ID] {
+			continue
+		}
+
+		group := []*PowerNode{start}
+		visited[start.ID] = true
+
+		supply, load := 0.0, 0.0
+		for i := 0; i < len(group); i++ {
+			node := group[i]
+
+			if node.Kind == PowerSource && node.On {
+				supply += POWER_SOURCE_CAPACITY
+			}
+			load += POWER_LOAD[node.Kind]
+
+			for _, next := range neighbors[node.ID] {
+				if !visited[next.ID] {
+					visited[next.ID] = true
+					group = append(group, next)
+				}
+			}
+		}
+
+		powered := supply > 0 && supply >= load
+		for _, node := range group {
+			node.powered = powered && node.Conducts()
+		}
+	}
+}
+
+// Consumer finds the node of the given kind in the cell at pos, or nil when that equipment
+// isn't wired.
+func (n *PowerNetwork) Consumer(kind PowerKind, pos Vec3, face FaceIndex) *PowerNode {
+	for _, node := range n.Nodes {
+		if node.Kind == kind && node.Position == pos && (kind != PowerDoor || node.Face == face) {
+			return node
+		}
+	}
+	return nil
+}
+
+// UpdatePower solves the network and stalls motorised doors that have lost power.
+func (g *Game) UpdatePower() {
+	network := &g.Level.Power
+	network.Solve()
+
+	for _, ref := range g.Level.doors {
+		node := network.Consumer(PowerDoor, ref.Cell.Position, ref.Index)
+		ref.Face().SetStalled(g, node != nil && !node.Powered())
+	}
+}
+
+// SetStalled holds a door at whatever angle it has, or lets it swing again.
+func (face *Face) SetStalled(g *Game, stalled bool) {
+	if stalled && face.stall == nil {
+		angle := face.body.Angle()
+		face.stall = g.Space.AddConstraint(cp.NewRotaryLimitJoint(g.Space.StaticBody, face.body, angle, angle))
+		face.stall.SetMaxForce(1e8)
+	}
+	if !stalled && face.stall != nil {
+		g.Space.RemoveConstraint(face.stall)
+		face.stall = nil
+	}
+}
+
+// Powered tells whether the car may move. Any elevator node wired into the shaft decides.
+func (e *Elevator) Powered(g *Game) bool {
+	for _, node := range g.Level.Power.Nodes {
+		if node.Kind == PowerElevator && e.Contains(node.Position) {
+			return node.Powered()
+		}
+	}
+	return true
+}
+
+func (node *PowerNode) Center() Vec3 {
+	center := node.Position.AddXYZ(0.5, 0.5, 0.5)
+	if node.Kind == PowerDoor {
+		center = center.Add(FACE_DIRECTION[node.Face].Scale(0.5))
+	}
+	return center
+}
+
+// Draw shows the generators and switches, each lit by the lights within its own cell.
+func (n *PowerNetwork) Draw(g *Game, maxY int) {
+	for _, node := range n.Nodes {
+		if node.Position.Y > float64(maxY) {
+			continue
+		}
+		g.SelectLightsAround(node.Center(), 0.5)
+
+		switch node.Kind {
+		case PowerSource:
+			col := rl.DarkGray
+			if node.On {
+				col = rl.Orange
+			}
+			rl.DrawCubeV(node.Position.AddXYZ(0.5, 0.2, 0.5).Raylib(), NewVec3(0.4, 0.4, 0.4).Raylib(), col)
+		case PowerSwitch:
+			col := rl.Maroon
+			if node.On {
+				col = rl.Green
+			}
+			rl.DrawCubeV(node.Center().Raylib(), NewVec3(0.1, 0.15, 0.1).Raylib(), col)
+		}
+	}
+}
+
+// DrawOverlay shows the wires, lit where they carry power.
+func (n *PowerNetwork) DrawOverlay(g *Game) {
+	for _, wire := range n.Wires {
+		a, b := n.Node(wire.A), n.Node(wire.B)
+		if a == nil || b == nil {
+			continue
+		}
+
+		col := color.RGBA{100, 100, 100, 255}
+		if a.Powered() && b.Powered() {
+			col = rl.Yellow
+		}
+		rl.DrawLine3D(a.Center().Raylib(), b.Center().Raylib(), col)
+	}
+
+	for _, node := range n.Nodes {
+		rl.DrawSphereWires(node.Center().Raylib(), 0.08, 4, 4, rl.Orange)
+	}
+}
+
+func (node *PowerNode) InteractPosition() Vec3 {
+	return node.Center()
+}
+
+func (node *PowerNode) InteractPrompt(g *Game, p *Player) string {
+	name := "switch"
+	if node.Kind == PowerSource {
+		name = "generator"
+	}
+
+	if node.On {
+		return "Turn off " + name
+	}
+	return "Turn on " + name
+}
+
+func (node *PowerNode) Interact(g *Game, p *Player) {
+	node.On = !node.On
+}
+
+// Interactables lists the switches and sources the player can flip.
+func (n *PowerNetwork) Interactables() []Interactable {
+	var targets []Interactable
+	for _, node := range n.Nodes {
+		if node.Kind == PowerSource || node.Kind == PowerSwitch {
+			targets = append(targets, node)
+		}
+	}
+	return targets
+}
+
+func (n *PowerNetwork) Nearest(pos Vec3, maxDistance float64) *PowerNode {
+	var nearest *PowerNode
+	nearestDistance := maxDistance
+
+	for _, node := range n.Nodes {
+		if math.Floor(pos.Y) != node.Position.Y {
+			continue
+		}
+		if distance := node.Center().To2D().Distance(pos.To2D()); distance <= nearestDistance {
+			nearest = node
+			nearestDistance = distance
+		}
+	}
+	return nearest
+}
@@ -13,17 +13,25 @@ const (
 	LIGHT_POINT
 	LIGHT_SPOT
 )
-const MAX_LIGHTS = 4
+
+// MAX_LIGHTS must match the define in lighting.fs.
+const MAX_LIGHTS = 16
 
 type MainShader struct {
 	shader rl.Shader
 
-	LightI int
+	LightI    int
+	LightBase int
 
 	UVClamp UniformVec4 `glsl:"uvClamp"`
 
 	ShadowMap UniformTexture `glsl:"shadowMap"`
 
+	SunShadows   UniformInt     `glsl:"sunShadows"`
+	SunShadowMap UniformTexture `glsl:"sunShadowMap"`
+	SunMatrix    UniformMat4    `glsl:"sunMatrix"`
+	LightMasks   UniformTexture `glsl:"lightMasks"`
+
 	PlayerPosition UniformVec3 `glsl:"playerPosition"`
 	// PlayerViewResolution UniformVec2 `glsl:"iResolution"`
 
@@ -38,6 +46,8 @@ type MainShader struct {
 	LightCutOff      [MAX_LIGHTS]UniformFloat `glsl:"lights[%d].cutOff"`
 	LightOuterCutOff [MAX_LIGHTS]UniformFloat `glsl:"lights[%d].outerCutOff"`
 	LightStrength    [MAX_LIGHTS]UniformFloat `glsl:"lights[%d].strength"`
+	LightRadius      [MAX_LIGHTS]UniformFloat `glsl:"lights[%d].radius"`
+	LightMask        [MAX_LIGHTS]UniformInt   `glsl:"lights[%d].mask"`
 }
 
 func (m *MainShader) GetRaylibShader() rl.Shader {
@@ -56,7 +66,8 @@ func (m *MainShader) LightDirectional(direction Vec3, color rl.Color, strength f
 	m.LightTarget[m.LightI].SetVec3(direction)
 	m.LightColor[m.LightI].SetColor(color)
 	m.LightStrength[m.LightI].Set(strength)
-
+	m.LightRadius[m.LightI].Set(0)
+	m.LightMask[m.LightI].Set(-1)
 }
 
 func (m *MainShader) LightSpot(position Vec3, target Vec3, cutoff float64, outerCutOff float64, color rl.Color, strength float64) {
@@ -69,7 +80,8 @@ func (m *MainShader) LightSpot(position Vec3, target Vec3, cutoff float64, outer
 	m.LightOuterCutOff[m.LightI].Set(math.Cos(float64(outerCutOff * rl.Deg2rad)))
 	m.LightColor[m.LightI].SetColor(color)
 	m.LightStrength[m.LightI].Set(strength)
-
+	m.LightRadius[m.LightI].Set(0)
+	m.LightMask[m.LightI].Set(-1)
 }
 
 func (m *MainShader) LightPoint(position Vec3, color rl.Color, strength float64) {
@@ -79,7 +91,34 @@ func (m *MainShader) LightPoint(position Vec3, color rl.Color, strength float64)
 	m.LightPosition[m.LightI].SetVec3(position)
 	m.LightColor[m.LightI].SetColor(color)
 	m.LightStrength[m.LightI].Set(strength)
+	m.LightRadius[m.LightI].Set(0)
+	m.LightMask[m.LightI].Set(-1)
+}
+
+// LightFalloff fades the last light out to nothing at radius. Lights without one reach
+// everywhere.
+func (m *MainShader) LightFalloff(radius float64) {
+	m.LightRadius[m.LightI].Set(radius)
+}
 
+// LightMaskTile limits the last light to what its tile of the light mask atlas shows.
+func (m *MainShader) LightMaskTile(tile int) {
+	m.LightMask[m.LightI].Set(int32(tile))
+}
+
+// ResetLights starts a frame without any lights. Lights added before KeepLights stay set for
+// every draw call of the frame; the ones added after it only until the next UpdateValues.
+func (m *MainShader) ResetLights() {
+	m.LightI = -1
+	m.LightBase = -1
+}
+
+func (m *MainShader) KeepLights() {
+	m.LightBase = m.LightI
+}
+
+func (m *MainShader) LightsFree() int {
+	return MAX_LIGHTS - 1 - m.LightI
 }
 
 func (m *MainShader) UpdateValues() {
@@ -87,7 +126,7 @@ func (m *MainShader) UpdateValues() {
 	for i := m.LightI + 1; i < MAX_LIGHTS; i++ {
 		m.LightEnabled[i].Set(0)
 	}
-	m.LightI = -1
+	m.LightI = m.LightBase
 }
 
 type TransitionShader struct {
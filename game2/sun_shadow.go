@@ -0,0 +1,105 @@
+package game2
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// The sun's shadow map covers SUN_SHADOW_SIZE units around the player, seen from
+// SUN_SHADOW_DISTANCE away along the sun's direction. Below SUN_SHADOW_MIN_ELEVATION the sun
+// is too low, or set, and casts no shadows. Stations have no sun to cast any.
+const SUN_SHADOW_RESOLUTION = 2048
+const SUN_SHADOW_SIZE = 24.0
+const SUN_SHADOW_DISTANCE = 30.0
+const SUN_SHADOW_MIN_ELEVATION = 0.05
+
+type ShadowShader struct {
+	shader rl.Shader
+}
+
+func (m *ShadowShader) GetRaylibShader() rl.Shader {
+	return m.shader
+}
+
+func (m *ShadowShader) SetRaylibShader(shader rl.Shader) {
+	m.shader = shader
+}
+
+// WithModelShader draws every loaded model with another shader for the duration of fn.
+func (g *Game) WithModelShader(shader Shader, fn func()) {
+	setShader := func(shader rl.Shader) {
+		for _, model := range g.Models {
+			mats := model.GetMaterials()
+			for i := range mats {
+				mats[i].Shader = shader
+			}
+		}
+	}
+
+	setShader(shader.GetRaylibShader())
+	fn()
+	setShader(g.MainShader.GetRaylibShader())
+}
+
+// SunShadowCovers tells whether pos lies inside the square the shadow map covers around the
+// player. Beyond it the sun shines unshadowed.
+func (g *Game) SunShadowCovers(pos Vec3, direction Vec3) bool {
+	right := direction.CrossProduct(Z).Normalize()
+	up := right.CrossProduct(direction).Normalize()
+	delta := pos.Subtract(g.Player.Position3D())
+
+	return math.Abs(delta.DotProduct(right)) <= SUN_SHADOW_SIZE/2 && math.Abs(delta.DotProduct(up)) <= SUN_SHADOW_SIZE/2
+}
+
+// RenderSunShadow draws the depth of the level as seen by the sun into SunShadowTexture and
+// hands it to the main shader, along with the matrix that maps world positions into it.
+func (g *Game) RenderSunShadow(direction Vec3, maxY int) {
+	if g.IsStation || -direction.Y < SUN_SHADOW_MIN_ELEVATION {
+		g.MainShader.SunShadows.Set(0)
+		return
+	}
+
+	center := g.Player.Position3D()
+
+	// The camera sees a square across the sun's direction, stretched out along it. Between the
+	// ground and maxY that covers everything within reach of the player horizontally.
+	diagonal := SUN_SHADOW_SIZE / 2 * math.Sqrt2
+	height := max(center.Y, float64(maxY+1)-center.Y) + diagonal
+	reach := diagonal + height*direction.To2D().Length()/-direction.Y
+	camera := Camera3D{
+		Position:   center.Subtract(direction.Scale(SUN_SHADOW_DISTANCE)),
+		Target:     center,
+		Up:         Z,
+		Fovy:       SUN_SHADOW_SIZE,
+		Projection: rl.CameraOrthographic,
+	}
+
+	var sunMatrix rl.Matrix
+
+	BeginTextureMode(g.SunShadowTexture, func() {
+		rl.ClearBackground(rl.White)
+
+		BeginMode3D(camera, func() {
+			sunMatrix = rl.MatrixMultiply(rl.GetMatrixModelview(), rl.GetMatrixProjection())
+
+			g.WithModelShader(g.ShadowShader, func() {
+				for chunkPos, chunk := range g.Level.Chunks {
+					aa := chunkPos.Scale(float64(CHUNK_WIDTH))
+					bb := aa.Add(NewVec2(float64(CHUNK_WIDTH), float64(CHUNK_WIDTH)))
+					if bb.X < center.X-reach || aa.X > center.X+reach || bb.Y < center.Z-reach || aa.Y > center.Z+reach {
+						continue
+					}
+					g.Level.DrawChunk(g, chunk, maxY)
+				}
+				EachEntity(g, func(e *Elevator) {
+					e.Draw3D(g, maxY)
+				})
+			})
+		})
+	})
+
+	g.MainShader.SunShadows.Set(1)
+	g.MainShader.SunShadowMap.Set(g.SunShadowTexture.Texture)
+	g.MainShader.SunMatrix.Set(sunMatrix)
+}
@@ -0,0 +1,197 @@
+package game2
+
+import (
+	"encoding/gob"
+	"image/color"
+
+	"github.com/gen2brain/raylib-go/raygui"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const PLAYER_SPO2 = 100.0
+
+// On stations SpO2 drains by PLAYER_SPO2_DRAIN per second, on Earth's breathable air it
+// recovers by PLAYER_SPO2_RECOVERY. Below PLAYER_HYPOXIA the player loses health, faster the
+// lower it drops.
+const PLAYER_SPO2_DRAIN = 0.5
+const PLAYER_SPO2_RECOVERY = 5.0
+const PLAYER_HYPOXIA = 80.0
+const PLAYER_HYPOXIA_DAMAGE = 0.5
+
+const OXYGEN_TANK_CAPACITY = 100.0
+const OXYGEN_TANK_TARGET = 95.0
+const OXYGEN_TANK_FLOW = 2.0
+const BATTERY_CAPACITY = 100.0
+const AIR_PURIFIER_RATE = 2.0
+
+func init() {
+	gob.Register(&ItemOxygenTank{})
+	gob.Register(&ItemBattery{})
+	gob.Register(&ItemAirPurifier{})
+}
+
+// ITEM_CATALOGUE lists the items the editor can place.
+var ITEM_CATALOGUE = []func() Item{
+	func() Item { return NewItemOxygenTank() },
+	func() Item { return NewItemBattery() },
+	func() Item { return &ItemAirPurifier{} },
+	func() Item { return NewItemFlashlight() },
+}
+
+// DefaultPlayerItems is what a new player carries, and what saves from before the inventory
+// get.
+func DefaultPlayerItems() []Item {
+	return []Item{NewItemOxygenTank(), NewItemFlashlight(), NewItemBattery()}
+}
+
+func (p *Player) UpdateVitals(g *Game) {
+	if p.Dead {
+		return
+	}
+
+	dt := g.TimeDelta.Seconds()
+	if g.IsStation {
+		p.SpO2 = max(p.SpO2-PLAYER_SPO2_DRAIN*dt, 0)
+	} else {
+		p.SpO2 = min(p.SpO2+PLAYER_SPO2_RECOVERY*dt, PLAYER_SPO2)
+	}
+
+	if p.SpO2 < PLAYER_HYPOXIA {
+		p.Damage(g, (PLAYER_HYPOXIA-p.SpO2)*PLAYER_HYPOXIA_DAMAGE*dt)
+	}
+}
+
+func (p *Player) DrawVitalsHUD(g *Game, line *LineLayout) {
+	raygui.ProgressBar(line.Next(200), "", "SpO2", float32(p.SpO2), 0, PLAYER_SPO2)
+}
+
+// ItemOxygenTank tops the player's SpO2 back up while it is open.
+type ItemOxygenTank struct {
+	Oxygen    float64
+	OxygenMax float64
+	Active    bool
+}
+
+func NewItemOxygenTank() *ItemOxygenTank {
+	return &ItemOxygenTank{
+		Oxygen:    OXYGEN_TANK_CAPACITY,
+		OxygenMax: OXYGEN_TANK_CAPACITY,
+	}
+}
+
+func (item *ItemOxygenTank) Name() string {
+	return "Oxygen Tank"
+}
+
+func (item *ItemOxygenTank) Color() color.RGBA {
+	return rl.SkyBlue
+}
+
+func (item *ItemOxygenTank) UpdateInventory(g *Game, p *Player) {
+	if !item.Active {
+		return
+	}
+	if item.Oxygen <= 0 {
+		item.Active = false
+		return
+	}
+
+	transfer := min(item.Oxygen, (OXYGEN_TANK_TARGET-p.SpO2)*OXYGEN_TANK_FLOW*g.TimeDelta.Seconds())
+	if transfer > 0 {
+		item.Oxygen -= transfer
+		p.SpO2 += transfer
+	}
+}
+
+func (item *ItemOxygenTank) UpdateWorld(g *Game, w *WorldItem) {}
+
+func (item *ItemOxygenTank) DrawHUD(g *Game, line *LineLayout) {
+	item.Active = raygui.CheckBox(line.Next(line.Height), "", item.Active)
+	raygui.ProgressBar(line.Next(196-line.Height), "", "", float32(item.Oxygen), 0, float32(item.OxygenMax))
+	line.Break(line.Height)
+}
+
+func (item *ItemOxygenTank) Save(g *Game) {}
+
+// ItemBattery stores power for other items to draw on.
+type ItemBattery struct {
+	Power    float64
+	PowerMax float64
+}
+
+func NewItemBattery() *ItemBattery {
+	return &ItemBattery{
+		Power:    BATTERY_CAPACITY,
+		PowerMax: BATTERY_CAPACITY,
+	}
+}
+
+func (item *ItemBattery) Name() string {
+	return "Battery"
+}
+
+func (item *ItemBattery) Color() color.RGBA {
+	return rl.Lime
+}
+
+func (item *ItemBattery) UpdateInventory(g *Game, p *Player) {}
+func (item *ItemBattery) UpdateWorld(g *Game, w *WorldItem)  {}
+
+func (item *ItemBattery) DrawHUD(g *Game, line *LineLayout) {
+	raygui.ProgressBar(line.Next(196), "", "", float32(item.Power), 0, float32(item.PowerMax))
+	line.Break(line.Height)
+}
+
+func (item *ItemBattery) Save(g *Game) {}
+
+// Battery finds a carried battery with charge left.
+func (p *Player) Battery() *ItemBattery {
+	for _, item := range p.Items {
+		if battery, ok := item.(*ItemBattery); ok && battery.Power > 0 {
+			return battery
+		}
+	}
+	return nil
+}
+
+// ItemAirPurifier turns battery power into oxygen for a carried tank that isn't full.
+type ItemAirPurifier struct {
+	Active bool
+}
+
+func (item *ItemAirPurifier) Name() string {
+	return "Air Purifier"
+}
+
+func (item *ItemAirPurifier) UpdateInventory(g *Game, p *Player) {
+	if !item.Active {
+		return
+	}
+
+	battery := p.Battery()
+
+	var tank *ItemOxygenTank
+	for _, other := range p.Items {
+		if oxygenTank, ok := other.(*ItemOxygenTank); ok && oxygenTank.Oxygen < oxygenTank.OxygenMax {
+			tank = oxygenTank
+			break
+		}
+	}
+
+	if battery == nil || tank == nil {
+		return
+	}
+
+	transfer := min(tank.OxygenMax-tank.Oxygen, battery.Power, AIR_PURIFIER_RATE*g.TimeDelta.Seconds())
+	battery.Power -= transfer
+	tank.Oxygen += transfer
+}
+
+func (item *ItemAirPurifier) UpdateWorld(g *Game, w *WorldItem) {}
+
+func (item *ItemAirPurifier) DrawHUD(g *Game, line *LineLayout) {
+	item.Active = raygui.CheckBox(line.Next(line.Height), "Active", item.Active)
+	line.Break(line.Height)
+}
+
+func (item *ItemAirPurifier) Save(g *Game) {}
@@ -1,9 +1,10 @@
 #version 330
 
-#define MAX_LIGHTS        4
+#define MAX_LIGHTS        16
 #define LIGHT_DIRECTIONAL 0
 #define LIGHT_POINT       1
 #define LIGHT_SPOT        2
+#define LIGHT_MASK_GRID   8
 
 in vec3 fragPosition;
 in vec2 fragTexCoord;
@@ -19,6 +20,11 @@ uniform sampler2D shadowMap;
 uniform vec3 playerPosition;
 uniform bool hideOutsideView;
 
+uniform bool sunShadows;
+uniform sampler2D sunShadowMap;
+uniform mat4 sunMatrix;
+uniform sampler2D lightMasks;
+
 out vec4 finalColor;
 
 struct Light {
@@ -31,12 +37,60 @@ struct Light {
   float cutOff;
   float outerCutOff;
   float strength;
+  float radius;
+  int mask;
 };
 
 uniform Light lights[MAX_LIGHTS];
 uniform vec4 ambient;
 uniform vec3 viewPos;
 
+float sunLit(vec3 normal, vec3 light)
+{
+  if (!sunShadows) return 1;
+
+  vec4 sunSpace = sunMatrix * vec4(fragPosition, 1.0);
+  vec3 coords = sunSpace.xyz / sunSpace.w * 0.5 + 0.5;
+  if (coords.x < 0 || coords.x > 1 || coords.y < 0 || coords.y > 1 || coords.z > 1) return 1;
+
+  // Depth spans raylib's whole 1000 unit clip range, so this is 0.05 to 0.2 units
+  float bias = max(0.0002 * (1.0 - dot(normal, light)), 0.00005);
+  vec2 texel = 1.0 / vec2(textureSize(sunShadowMap, 0));
+
+  float lit = 0;
+  for (float x = -1; x <= 1; x++) {
+    for (float y = -1; y <= 1; y++) {
+      float depth = dot(texture(sunShadowMap, coords.xy + vec2(x, y) * texel).rgb, vec3(1.0, 1.0/255.0, 1.0/65025.0));
+      lit += coords.z - bias > depth ? 0 : 1;
+    }
+  }
+  return lit / 9;
+}
+
+float falloff(Light light)
+{
+  if (light.radius <= 0) return 1;
+  float fade = clamp(1 - length(light.position - fragPosition) / light.radius, 0, 1);
+  return fade * fade;
+}
+
+// What the light can see of its own floor, from its tile of the mask atlas
+float masked(Light light)
+{
+  if (light.mask < 0) return 1;
+
+  float floorY = floor(light.position.y);
+  if (fragPosition.y < floorY - 0.25 || fragPosition.y > floorY + 1.1) return 0;
+
+  vec2 local = (fragPosition.xz - light.position.xz) / (light.radius * 2) + 0.5;
+  if (local.x < 0 || local.x > 1 || local.y < 0 || local.y > 1) return 0;
+
+  vec2 tile = vec2(mod(float(light.mask), LIGHT_MASK_GRID), floor(float(light.mask) / LIGHT_MASK_GRID));
+  vec2 uv = (tile + local) / LIGHT_MASK_GRID;
+  uv.y = 1 - uv.y;
+  return texture(lightMasks, uv).r;
+}
+
 void main()
 {
   // Texel color fetching from texture sampler
@@ -72,7 +126,7 @@ void main()
     if (lights[i].type == LIGHT_DIRECTIONAL) {
     
       vec3 light = -normalize(lights[i].target - lights[i].position);
-      float NdotL = max(dot(normal, light), 0.0);
+      float NdotL = max(dot(normal, light), 0.0) * sunLit(normal, light);
       
       lightDot += lights[i].color.rgb * NdotL * lights[i].strength;
 
@@ -83,7 +137,7 @@ void main()
     } else if (lights[i].type == LIGHT_POINT) {
       
       vec3 light = normalize(lights[i].position - fragPosition);
-      float NdotL = max(dot(normal, light), 0.0);
+      float NdotL = max(dot(normal, light), 0.0) * falloff(lights[i]) * masked(lights[i]);
       
       lightDot += lights[i].color.rgb * NdotL * lights[i].strength;
 
@@ -97,7 +151,7 @@ void main()
       vec3 rayDir = normalize(lights[i].position - lights[i].target); 
       float theta = dot(light, rayDir); 
       float epsilon = lights[i].cutOff - lights[i].outerCutOff;
-      float intensity = clamp((theta - lights[i].outerCutOff) / epsilon, 0.0, 1.0);
+      float intensity = clamp((theta - lights[i].outerCutOff) / epsilon, 0.0, 1.0) * falloff(lights[i]) * masked(lights[i]);
       
       
 
@@ -9,6 +9,10 @@ out vec4 fragColor;
 
 void main()
 {
-    float depth01 = vDepth; // clamp((vDepth - near) / (far - near), 0.0, 1.0);
-    fragColor = vec4(depth01, depth01, depth01, 1.0);
+    float depth01 = clamp(vDepth, 0.0, 0.9999);
+
+    // Spread the depth over three 8 bit channels, lighting.fs puts it back together
+    vec3 encoded = fract(depth01 * vec3(1.0, 255.0, 65025.0));
+    encoded -= encoded.yzz * vec3(1.0/255.0, 1.0/255.0, 0.0);
+    fragColor = vec4(encoded, 1.0);
 }
@@ -16,8 +16,7 @@ out vec3 fragPosition;
 out vec2 fragTexCoord;
 out vec4 fragColor;
 out vec3 fragNormal;
-
-// NOTE: Add your custom variables here
+out float vDepth;
 
 void main()
 {
@@ -29,4 +28,7 @@ void main()
 
     // Calculate final vertex position
     gl_Position = mvp*vec4(vertexPosition, 1.0);
+
+    // Orthographic, so w is 1 and this is already linear
+    vDepth = gl_Position.z*0.5 + 0.5;
 }
\ No newline at end of file